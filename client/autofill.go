@@ -0,0 +1,90 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Autofill set the Sequence of tx with NextSequence and its Fee from the fee method when they are zero,
+// the Sequence of a tx consuming a ticket stays 0. signers is the number of signers of a multi-signed tx,
+// which pays the fee once per signer more, and 0 for a single-signed tx. An EscrowFinish with a
+// fulfillment also pays its ExtraFeeUnits
+func (this *RpcClient) Autofill(tx data.Transaction, signers int) error {
+	base := tx.GetBase()
+	if base.Fee.IsZero() {
+		units := feeUnits(signers)
+		if finish, ok := types.Unticketed(tx).(*types.EscrowFinish); ok {
+			units += finish.ExtraFeeUnits()
+		}
+		fee, err := this.txFee(units)
+		if err != nil {
+			return fmt.Errorf("Autofill: %s", err)
+		}
+		base.Fee = *fee
+	}
+	// the sequence is filled last, so that an error doesn't leave an allocated sequence unused
+	if _, ticketed := types.GetTicketSequence(tx); base.Sequence == 0 && !ticketed {
+		sequence, err := this.NextSequence(base.Account)
+		if err != nil {
+			return fmt.Errorf("Autofill: %s", err)
+		}
+		base.Sequence = sequence
+	}
+	return nil
+}
+
+// GetTxFee return the fee of a tx with signers signatures, 0 for a single-signed tx. It is the higher
+// of the base fee and the open ledger fee, multiplied by signers+1 for a multi-signed tx
+func (this *RpcClient) GetTxFee(signers int) (*data.Value, error) {
+	return this.txFee(feeUnits(signers))
+}
+
+// feeUnits return the number of base fees of a tx with signers signatures
+func feeUnits(signers int) uint64 {
+	if signers > 0 {
+		return uint64(signers + 1)
+	}
+	return 1
+}
+
+// txFee return units times the higher of the base fee and the open ledger fee
+func (this *RpcClient) txFee(units uint64) (*data.Value, error) {
+	fee, err := this.GetFee()
+	if err != nil {
+		return nil, fmt.Errorf("GetTxFee: %s", err)
+	}
+	if fee == nil {
+		return nil, fmt.Errorf("GetTxFee: fee resp is empty")
+	}
+	baseFee, err := valueDrops(&fee.Drops.BaseFee)
+	if err != nil {
+		return nil, fmt.Errorf("GetTxFee: invalid base fee, err: %s", err)
+	}
+	openLedgerFee, err := valueDrops(&fee.Drops.OpenLedgerFee)
+	if err != nil {
+		return nil, fmt.Errorf("GetTxFee: invalid open ledger fee, err: %s", err)
+	}
+	if openLedgerFee > baseFee {
+		baseFee = openLedgerFee
+	}
+	return data.NewNativeValue(int64(baseFee * units))
+}
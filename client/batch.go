@@ -0,0 +1,111 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"sync/atomic"
+)
+
+// DefaultBatchParallelism is the number of concurrent requests Batch falls back to
+const DefaultBatchParallelism = 4
+
+// SetBatchParallelism set the number of concurrent requests used when the server does not support batch
+func (this *RpcClient) SetBatchParallelism(parallelism int) *RpcClient {
+	if parallelism < 1 {
+		parallelism = 1
+	}
+	this.batchParallelism = parallelism
+	return this
+}
+
+// Batch send all the items in one batch request, or as concurrent requests if the server does not
+// support batch. The result or error of every item is set in the item, the returned error is only
+// for a failure of the whole batch, e.g. when the server is busy, and the batch can be retried
+func (this *RpcClient) Batch(ctx context.Context, items []*BatchItem) error {
+	if len(items) == 0 {
+		return nil
+	}
+	if atomic.LoadInt32(&this.batchUnsupported) == 0 {
+		supported, err := this.sendBatch(ctx, items)
+		if err != nil {
+			return fmt.Errorf("Batch: %s", err)
+		}
+		if supported {
+			return nil
+		}
+		atomic.StoreInt32(&this.batchUnsupported, 1)
+	}
+	this.sendConcurrently(ctx, items)
+	return nil
+}
+
+// sendBatch return false if the server does not understand the batch method, any other failure is
+// returned as an error so that a transient one does not disable batch
+func (this *RpcClient) sendBatch(ctx context.Context, items []*BatchItem) (bool, error) {
+	reqs := make([]interface{}, 0, len(items))
+	for _, item := range items {
+		params := []interface{}{}
+		if item.Params != nil {
+			params = append(params, item.Params)
+		}
+		reqs = append(reqs, &JsonRpcRequest{Method: item.Method, Params: params})
+	}
+	respData, err := this.sendRpcRequestWithContext(ctx, RPC_BATCH, reqs)
+	if err != nil {
+		return false, fmt.Errorf("send batch req err: %w", err)
+	}
+	var resps []*rpcResp
+	if err := json.Unmarshal(respData, &resps); err != nil {
+		rpcErr := resultError(respData)
+		if rpcErr == nil {
+			return false, fmt.Errorf("unmarshal batch resp err: %s, origin resp is %s", err, Redact(respData))
+		}
+		if rpcErr.Name == "unknownCmd" {
+			return false, nil
+		}
+		return false, fmt.Errorf("batch resp failed: %w", rpcErr)
+	}
+	if len(resps) != len(items) {
+		return false, fmt.Errorf("batch resp has %d results for %d items", len(resps), len(items))
+	}
+	for i, item := range items {
+		item.Err = decodeResult(item.Method, resps[i].Result, item.Result)
+	}
+	return true, nil
+}
+
+func (this *RpcClient) sendConcurrently(ctx context.Context, items []*BatchItem) {
+	sem := make(chan struct{}, this.batchParallelism)
+	wg := &sync.WaitGroup{}
+	for _, item := range items {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(item *BatchItem) {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			item.Err = this.Call(ctx, item.Method, item.Params, item.Result)
+		}(item)
+	}
+	wg.Wait()
+}
@@ -0,0 +1,150 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"container/list"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+// Cache store the responses of validated ledgers and transactions, which never change. Implement it
+// to plug another backend than the in-memory LRUCache, it must be safe for concurrent use
+type Cache interface {
+	Get(key string) ([]byte, bool)
+	Add(key string, value []byte)
+}
+
+// LRUCache is an in-memory Cache evicting the least recently used responses
+type LRUCache struct {
+	lock  sync.Mutex
+	size  int
+	list  *list.List
+	items map[string]*list.Element
+}
+
+type lruEntry struct {
+	key   string
+	value []byte
+}
+
+// NewLRUCache return a cache holding at most size responses
+func NewLRUCache(size int) *LRUCache {
+	if size < 1 {
+		size = 1
+	}
+	return &LRUCache{size: size, list: list.New(), items: make(map[string]*list.Element)}
+}
+
+func (this *LRUCache) Get(key string) ([]byte, bool) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	elem, ok := this.items[key]
+	if !ok {
+		return nil, false
+	}
+	this.list.MoveToFront(elem)
+	return elem.Value.(*lruEntry).value, true
+}
+
+func (this *LRUCache) Add(key string, value []byte) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if elem, ok := this.items[key]; ok {
+		elem.Value.(*lruEntry).value = value
+		this.list.MoveToFront(elem)
+		return
+	}
+	this.items[key] = this.list.PushFront(&lruEntry{key: key, value: value})
+	if this.list.Len() > this.size {
+		oldest := this.list.Back()
+		this.list.Remove(oldest)
+		delete(this.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len return the number of cached responses
+func (this *LRUCache) Len() int {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.list.Len()
+}
+
+// SetCache cache the responses of validated ledgers and transactions in cache, nil disable caching
+func (this *RpcClient) SetCache(cache Cache) *RpcClient {
+	this.cache = cache
+	return this
+}
+
+type cacheableResult struct {
+	Result struct {
+		LedgerHash string `json:"ledger_hash"`
+		Validated  bool   `json:"validated"`
+		Status     string `json:"status"`
+	} `json:"result"`
+}
+
+// ledgerCacheKey return the key of a ledger request, empty if the request does not always select the same ledger
+func ledgerCacheKey(req *ledgerReqParam) string {
+	if !req.fixed() {
+		return ""
+	}
+	return fmt.Sprintf("ledger/%s/%t/%t/%t", req.LedgerSpecifier, req.Transactions, req.Expand, req.Binary)
+}
+
+func txCacheKey(hash string, binary bool) string {
+	return fmt.Sprintf("tx/%s/%t", strings.ToUpper(hash), binary)
+}
+
+// sendCachedRequest send the request unless the response of key is cached, a validated response is
+// cached under key and, for a ledger, under the key of its hash as well. An empty key is never read
+// from the cache, it is used for requests of a moving ledger such as validated. A response is returned
+// and cached only if verify, when not nil, accepts it
+func (this *RpcClient) sendCachedRequest(key string, method string, params []interface{}, verify func(respData []byte) error) ([]byte, error) {
+	if this.cache != nil && key != "" {
+		if respData, ok := this.cache.Get(key); ok {
+			return respData, nil
+		}
+	}
+	respData, err := this.sendRpcRequest(method, params)
+	if err != nil {
+		return nil, err
+	}
+	if verify != nil {
+		if err := verify(respData); err != nil {
+			return nil, err
+		}
+	}
+	if this.cache == nil {
+		return respData, nil
+	}
+	result := &cacheableResult{}
+	if err := json.Unmarshal(respData, result); err != nil || !result.Result.Validated || result.Result.Status != "success" {
+		return respData, nil
+	}
+	if key != "" {
+		this.cache.Add(key, respData)
+	}
+	if req, ok := params[0].(ledgerReqParam); ok && result.Result.LedgerHash != "" {
+		req.LedgerSpecifier = LedgerAtHash(result.Result.LedgerHash)
+		this.cache.Add(ledgerCacheKey(&req), respData)
+	}
+	return respData, nil
+}
@@ -0,0 +1,98 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/ripple-sdk/types"
+)
+
+// GetAccountChannels return one page of the payment channels from account, to destination only unless
+// destination is empty. marker is empty for the first page and the returned marker is empty after the
+// last page, limit 0 lets the node choose the page size
+func (this *RpcClient) GetAccountChannels(account, destination string, limit uint32, marker string, ledger ...LedgerSpecifier) (*AccountChannelsRes, error) {
+	accountChannelsReqParam := accountChannelsReqParam{
+		Account:            account,
+		DestinationAccount: destination,
+		Limit:              limit,
+		Marker:             marker,
+		LedgerSpecifier:    ledgerSpecifier(ledger),
+	}
+	respData, err := this.sendRpcRequest(RPC_ACCOUNT_CHANNELS, []interface{}{accountChannelsReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountChannels: send req err: %s", err)
+	}
+	result := &AccountChannelsRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountChannels: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetAccountChannels, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
+
+// GetAllAccountChannels return all the payment channels from account, to destination only unless
+// destination is empty, following the markers on the ledger of the first page as GetAllAccountObjects
+func (this *RpcClient) GetAllAccountChannels(account, destination string, ledger ...LedgerSpecifier) ([]*AccountChannel, error) {
+	spec := ledgerSpecifier(ledger)
+	var channels []*AccountChannel
+	marker := ""
+	for {
+		res, err := this.GetAccountChannels(account, destination, 0, marker, spec)
+		if err != nil {
+			return nil, err
+		}
+		channels = append(channels, res.Result.Channels...)
+		if res.Result.Marker == "" {
+			return channels, nil
+		}
+		if !spec.fixed() && res.Result.LedgerIndex != 0 {
+			spec = LedgerAtIndex(res.Result.LedgerIndex)
+		}
+		marker = res.Result.Marker
+	}
+}
+
+// Remaining return the drops the channel holds that have not been delivered yet
+func (this *AccountChannel) Remaining() uint64 {
+	if this.Balance > this.Amount {
+		return 0
+	}
+	return this.Amount - this.Balance
+}
+
+// VerifyClaim check claim is signed by the key of the channel and within its amount, which the
+// destination must do before accepting the claim as payment
+func (this *AccountChannel) VerifyClaim(claim *types.ChannelClaim) error {
+	switch {
+	case claim.Channel != this.ChannelId:
+		return fmt.Errorf("VerifyClaim: claim is for channel %s, not %s", claim.Channel, this.ChannelId)
+	case claim.PublicKey != this.PublicKeyHex:
+		return fmt.Errorf("VerifyClaim: claim is signed by %s, not the channel key %s", claim.PublicKey, this.PublicKeyHex)
+	case claim.Amount > this.Amount:
+		return fmt.Errorf("VerifyClaim: claim of %d drops is above the channel amount %d", claim.Amount, this.Amount)
+	}
+	if err := claim.Verify(); err != nil {
+		return fmt.Errorf("VerifyClaim: %s", err)
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Checks are the outstanding checks of an account, Sent are written by the account and Received can be
+// cashed by it
+type Checks struct {
+	Sent     []*data.Check
+	Received []*data.Check
+}
+
+// GetChecks return the outstanding checks of account, account_objects lists a check for both its
+// source and its destination. Expired checks are listed until they are cancelled
+func (this *RpcClient) GetChecks(account string, ledger ...LedgerSpecifier) (*Checks, error) {
+	owner, err := data.NewAccountFromAddress(account)
+	if err != nil {
+		return nil, fmt.Errorf("GetChecks: invalid address %s, err: %s", account, err)
+	}
+	objects, err := this.GetAllAccountObjects(account, AccountObjectCheck, ledger...)
+	if err != nil {
+		return nil, fmt.Errorf("GetChecks: %s", err)
+	}
+	checks := &Checks{}
+	for _, object := range objects {
+		check, ok := object.(*data.Check)
+		if !ok || check.Account == nil || check.Destination == nil {
+			return nil, fmt.Errorf("GetChecks: invalid check object %s", object.GetLedgerIndex())
+		}
+		if check.Account.Equals(*owner) {
+			checks.Sent = append(checks.Sent, check)
+		} else {
+			checks.Received = append(checks.Received, check)
+		}
+	}
+	return checks, nil
+}
@@ -0,0 +1,91 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLRUCache(t *testing.T) {
+	cache := client.NewLRUCache(2)
+	cache.Add("a", []byte("1"))
+	cache.Add("b", []byte("2"))
+	_, ok := cache.Get("a")
+	assert.True(t, ok)
+	cache.Add("c", []byte("3"))
+	assert.Equal(t, 2, cache.Len())
+	_, ok = cache.Get("b")
+	assert.False(t, ok)
+	value, ok := cache.Get("a")
+	assert.True(t, ok)
+	assert.Equal(t, []byte("1"), value)
+	cache.Add("a", []byte("4"))
+	value, _ = cache.Get("a")
+	assert.Equal(t, []byte("4"), value)
+}
+
+func TestCache(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	cache := client.NewLRUCache(16)
+	c := s.Client().SetCache(cache)
+
+	ledger, err := c.GetLedger(1)
+	assert.Nil(t, err)
+	cached, err := c.GetLedger(1)
+	assert.Nil(t, err)
+	assert.Equal(t, ledger, cached)
+	_, err = c.GetLedgerBinary(1)
+	assert.Nil(t, err)
+	_, err = c.GetLedgerBinary(1)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(s.Requests()))
+	// ledgers are cached under their index and hash
+	assert.Equal(t, 4, cache.Len())
+
+	// errors are not cached
+	_, err = c.GetLedgerBinary(2)
+	assert.NotNil(t, err)
+	_, err = c.GetLedgerBinary(2)
+	assert.NotNil(t, err)
+	assert.Equal(t, 4, len(s.Requests()))
+
+	s.Handle(client.RPC_TX, func(params json.RawMessage) (interface{}, error) {
+		return map[string]interface{}{"TransactionType": "Payment", "hash": "01", "validated": false}, nil
+	})
+	for i := 0; i < 2; i++ {
+		tx, err := c.GetTx("01")
+		assert.Nil(t, err)
+		assert.False(t, tx.Validated)
+	}
+	assert.Equal(t, 6, len(s.Requests()))
+
+	s.Handle(client.RPC_TX, func(params json.RawMessage) (interface{}, error) {
+		return map[string]interface{}{"TransactionType": "Payment", "hash": "01", "validated": true}, nil
+	})
+	for i := 0; i < 2; i++ {
+		tx, err := c.GetTx("01")
+		assert.Nil(t, err)
+		assert.True(t, tx.Validated)
+	}
+	assert.Equal(t, 7, len(s.Requests()))
+}
@@ -0,0 +1,129 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPaymentChannel(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	source, _ := newAccount(t, "source")
+	address := source.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	destination, _ := newAccount(t, "destination")
+	_, err = s.FundAccount(destination.Account.String(), 10000000)
+	assert.Nil(t, err)
+	other, _ := newAccount(t, "other")
+	_, err = s.FundAccount(other.Account.String(), 10000000)
+	assert.Nil(t, err)
+	amount, err := data.NewAmount("10000000")
+	assert.Nil(t, err)
+
+	create, err := types.GeneratePaymentChannelCreate(source.Account, destination.Account, *amount, 20, source.PublicKey(), 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, create))
+	id := types.GetPayChannelIndex(source.Account, destination.Account, create.Sequence)
+	otherCreate, err := types.GeneratePaymentChannelCreate(source.Account, other.Account, *amount, 20, source.PublicKey(), 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, otherCreate))
+
+	channels, err := c.GetAllAccountChannels(address, "", client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(channels))
+	page, err := c.GetAccountChannels(address, "", 1, "", client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(page.Result.Channels))
+	assert.NotEqual(t, "", page.Result.Marker)
+	page, err = c.GetAccountChannels(address, "", 1, page.Result.Marker, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(page.Result.Channels))
+	assert.Equal(t, "", page.Result.Marker)
+	channels, err = c.GetAllAccountChannels(address, destination.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(channels))
+	channel := channels[0]
+	assert.Equal(t, id, channel.ChannelId)
+	assert.Equal(t, destination.Account, channel.DestinationAccount)
+	assert.Equal(t, uint64(10000000), channel.Amount)
+	assert.Equal(t, uint64(0), channel.Balance)
+	assert.Equal(t, uint32(20), channel.SettleDelay)
+	assert.Equal(t, source.PublicKey(), channel.PublicKeyHex)
+	assert.Equal(t, byte('a'), channel.PublicKey[0])
+	assert.Equal(t, uint64(10000000), channel.Remaining())
+
+	// the source pays off ledger and the destination checks each claim against the channel
+	first, err := source.SignChannelClaim(id, 1000000)
+	assert.Nil(t, err)
+	assert.Nil(t, channel.VerifyClaim(first))
+	claim, err := source.SignChannelClaim(id, 2500000)
+	assert.Nil(t, err)
+	assert.Nil(t, channel.VerifyClaim(claim))
+	forged, err := destination.SignChannelClaim(id, 2500000)
+	assert.Nil(t, err)
+	assert.NotNil(t, channel.VerifyClaim(forged))
+	tooMuch, err := source.SignChannelClaim(id, 20000000)
+	assert.Nil(t, err)
+	assert.NotNil(t, channel.VerifyClaim(tooMuch))
+
+	redeem, err := types.GeneratePaymentChannelClaim(destination.Account, id, claim.Amount, claim, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, destination, redeem))
+	assert.Equal(t, int64(10000000+2500000-10), engine.Drops(s.Account(destination.Account.String()).Balance))
+	redeem, err = types.GeneratePaymentChannelClaim(destination.Account, id, claim.Amount, claim, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecUNFUNDED_PAYMENT", submitValidated(t, s, c, destination, redeem))
+
+	fund, err := types.GeneratePaymentChannelFund(source.Account, id, *amount, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, fund))
+	channels, err = c.GetAllAccountChannels(address, destination.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(20000000), channels[0].Amount)
+	assert.Equal(t, uint64(2500000), channels[0].Balance)
+	assert.Equal(t, uint64(17500000), channels[0].Remaining())
+
+	// the source can only close the channel after the settle delay
+	closing, err := types.GeneratePaymentChannelClaim(source.Account, id, 0, nil, data.TxClose, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, closing))
+	channels, err = c.GetAllAccountChannels(address, destination.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(channels))
+	assert.NotEqual(t, uint32(0), channels[0].Expiration)
+	s.CloseLedger()
+	s.CloseLedger()
+	balance := engine.Drops(s.Account(address).Balance)
+	closing, err = types.GeneratePaymentChannelClaim(source.Account, id, 0, nil, data.TxClose, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, closing))
+	assert.Equal(t, balance+17500000-10, engine.Drops(s.Account(address).Balance))
+	channels, err = c.GetAllAccountChannels(address, destination.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(channels))
+}
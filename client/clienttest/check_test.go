@@ -0,0 +1,110 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	source, _ := newAccount(t, "source")
+	address := source.Account.String()
+	_, err := s.FundAccount(address, 50000000)
+	assert.Nil(t, err)
+	destination, _ := newAccount(t, "destination")
+	_, err = s.FundAccount(destination.Account.String(), 10000000)
+	assert.Nil(t, err)
+	other, _ := newAccount(t, "other")
+	_, err = s.FundAccount(other.Account.String(), 10000000)
+	assert.Nil(t, err)
+
+	now := s.ValidatedLedger().CloseTime.Uint32()
+	var ids []data.Hash256
+	for _, check := range []struct {
+		sendMax    string
+		expiration uint32
+	}{{"5000000", now + 1000}, {"3000000", 0}} {
+		create, err := types.GenerateCheckCreate(source.Account, destination.Account, newAmount(t, check.sendMax), check.expiration, nil, data.Value{}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, create))
+		ids = append(ids, types.GetCheckIndex(source.Account, create.Sequence))
+	}
+	sent, err := c.GetChecks(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(sent.Sent))
+	assert.Equal(t, 0, len(sent.Received))
+	assert.Equal(t, ids[0], *sent.Sent[0].LedgerIndex)
+	assert.Equal(t, "5", sent.Sent[0].SendMax.Value.String())
+	received, err := c.GetChecks(destination.Account.String(), client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(received.Sent))
+	assert.Equal(t, 2, len(received.Received))
+	// only the source pays the reserve of the checks
+	assert.Equal(t, uint32(2), *s.Account(address).OwnerCount)
+	assert.Equal(t, uint32(0), *s.Account(destination.Account.String()).OwnerCount)
+
+	cash, err := types.GenerateCheckCash(other.Account, ids[0], newAmount(t, "5000000"), data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_PERMISSION", submitValidated(t, s, c, other, cash))
+	cash, err = types.GenerateCheckCash(destination.Account, ids[0], newAmount(t, "5000000"), data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, destination, cash))
+	cash, err = types.GenerateCheckCash(destination.Account, ids[0], newAmount(t, "5000000"), data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_ENTRY", submitValidated(t, s, c, destination, cash))
+	cash, err = types.GenerateCheckCash(destination.Account, ids[1], newAmount(t, "4000000"), data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecPATH_PARTIAL", submitValidated(t, s, c, destination, cash))
+	cash, err = types.GenerateCheckCashDeliverMin(destination.Account, ids[1], newAmount(t, "1000000"), data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, destination, cash))
+	assert.Equal(t, int64(10000000+5000000+3000000-40), engine.Drops(s.Account(destination.Account.String()).Balance))
+	assert.Equal(t, int64(50000000-5000000-3000000-20), engine.Drops(s.Account(address).Balance))
+
+	// an expired check can not be cashed, and anyone can cancel it
+	create, err := types.GenerateCheckCreate(source.Account, destination.Account, newAmount(t, "1000000"), s.ValidatedLedger().CloseTime.Uint32()+25, nil, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, source, create))
+	ids = append(ids, types.GetCheckIndex(source.Account, create.Sequence))
+	cancel := types.GenerateCheckCancel(other.Account, ids[2], data.Value{}, 0)
+	assert.Equal(t, "tecNO_PERMISSION", submitValidated(t, s, c, other, cancel))
+	s.CloseLedger()
+	cash, err = types.GenerateCheckCash(destination.Account, ids[2], newAmount(t, "1000000"), data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecEXPIRED", submitValidated(t, s, c, destination, cash))
+	cancel = types.GenerateCheckCancel(other.Account, ids[2], data.Value{}, 0)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, other, cancel))
+
+	sent, err = c.GetChecks(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(sent.Sent))
+	received, err = c.GetChecks(destination.Account.String(), client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(received.Received))
+	assert.Equal(t, uint32(0), *s.Account(address).OwnerCount)
+}
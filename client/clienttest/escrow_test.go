@@ -0,0 +1,122 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscrow(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	destination, _ := newAccount(t, "destination")
+	_, err = s.FundAccount(destination.Account.String(), 10000000)
+	assert.Nil(t, err)
+	amount, err := data.NewAmount("5000000")
+	assert.Nil(t, err)
+	preimage, err := types.GeneratePreimage()
+	assert.Nil(t, err)
+	condition, err := types.PreimageCondition(preimage)
+	assert.Nil(t, err)
+	fulfillment, err := types.PreimageFulfillment(preimage)
+	assert.Nil(t, err)
+
+	now := s.ValidatedLedger().CloseTime.Uint32()
+	create, err := types.GenerateEscrowCreate(owner.Account, destination.Account, *amount, now+15, 0, condition, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, owner, create))
+	escrows, err := c.GetAllAccountObjects(address, client.AccountObjectEscrow)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(escrows))
+	assert.Equal(t, condition, escrows[0].(*data.Escrow).Condition.Bytes())
+	assert.Equal(t, int64(100000000-10-5000000), engine.Drops(s.Account(address).Balance))
+
+	finish, err := types.GenerateEscrowFinish(destination.Account, owner.Account, create.Sequence, condition, fulfillment, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_PERMISSION", submitValidated(t, s, c, destination, finish))
+	otherPreimage, err := types.GeneratePreimage()
+	assert.Nil(t, err)
+	otherFulfillment, err := types.PreimageFulfillment(otherPreimage)
+	assert.Nil(t, err)
+	*finish.Fulfillment = otherFulfillment
+	finish.Fee, finish.Sequence = data.Value{}, 0
+	assert.Equal(t, "tecCRYPTOCONDITION_ERROR", submitValidated(t, s, c, destination, finish))
+	finish, err = types.GenerateEscrowFinish(destination.Account, owner.Account, create.Sequence, condition, fulfillment, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, destination, finish))
+	// the fulfillment costs 32 base fees plus one per 16 bytes
+	assert.Equal(t, "350", engine.DropsString(&finish.Fee))
+	assert.Equal(t, int64(10000000+5000000-350*3), engine.Drops(s.Account(destination.Account.String()).Balance))
+	escrows, err = c.GetAllAccountObjects(address, client.AccountObjectEscrow)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(escrows))
+
+	// a multisig account locks and cancels an escrow
+	signers := make([]*types.Account, 2)
+	var entries []types.SignerEntry
+	for i, passphrase := range []string{"signer1", "signer2"} {
+		signers[i], _ = newAccount(t, passphrase)
+		entries = append(entries, types.NewSignerEntry(signers[i].Account, 1))
+	}
+	signerListSet, err := types.GenerateSignerListSet(owner.Account, 2, entries, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, owner, signerListSet))
+	multiSubmit := func(tx data.Transaction) string {
+		assert.Nil(t, c.Autofill(tx, len(signers)))
+		rawTx, err := types.SerializeRawMultiSignTransaction(tx)
+		assert.Nil(t, err)
+		multiSigned, err := types.DeserializeRawMultiSignTransaction(rawTx)
+		assert.Nil(t, err)
+		for _, signer := range signers {
+			signed, err := signer.MultiSignTransaction(rawTx)
+			assert.Nil(t, err)
+			types.AddSigners(multiSigned, signed.GetBase().Signers...)
+		}
+		res, err := c.SubmitMultisignedTx(multiSigned)
+		assert.Nil(t, err)
+		assert.Equal(t, "tesSUCCESS", res.Result.EngineResult)
+		s.CloseLedger()
+		result, err := c.GetTx(res.Result.TxJson.Hash, client.LedgerValidated)
+		assert.Nil(t, err)
+		return result.MetaData.TransactionResult.String()
+	}
+	now = s.ValidatedLedger().CloseTime.Uint32()
+	create, err = types.GenerateEscrowCreate(owner.Account, destination.Account, *amount, now+5, now+15, nil, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", multiSubmit(create))
+	cancel := types.GenerateEscrowCancel(owner.Account, owner.Account, create.Sequence, data.Value{}, 0)
+	assert.Equal(t, "tecNO_PERMISSION", multiSubmit(cancel))
+	s.CloseLedger()
+	cancel = types.GenerateEscrowCancel(owner.Account, owner.Account, create.Sequence, data.Value{}, 0)
+	assert.Equal(t, "tesSUCCESS", multiSubmit(cancel))
+	escrows, err = c.GetAllAccountObjects(address, client.AccountObjectEscrow)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, len(escrows))
+}
@@ -0,0 +1,969 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"bytes"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/rubblelabs/ripple/websockets"
+)
+
+type ledgerParams struct {
+	LedgerIndex  interface{} `json:"ledger_index"`
+	LedgerHash   string      `json:"ledger_hash"`
+	Transactions bool        `json:"transactions"`
+	Expand       bool        `json:"expand"`
+	Binary       bool        `json:"binary"`
+}
+
+type txParams struct {
+	Transaction string `json:"transaction"`
+	Binary      bool   `json:"binary"`
+}
+
+type accountParams struct {
+	Account     string      `json:"account"`
+	LedgerIndex interface{} `json:"ledger_index"`
+	LedgerHash  string      `json:"ledger_hash"`
+}
+
+type accountObjectsParams struct {
+	Account     string      `json:"account"`
+	Type        string      `json:"type"`
+	Limit       int         `json:"limit"`
+	Marker      string      `json:"marker"`
+	LedgerIndex interface{} `json:"ledger_index"`
+	LedgerHash  string      `json:"ledger_hash"`
+}
+
+// objectTypes map the type filter of account_objects to the ledger entry type
+var objectTypes = map[string]data.LedgerEntryType{
+	client.AccountObjectCheck:          data.CHECK,
+	client.AccountObjectDepositPreauth: data.DEPOSIT_PRE_AUTH,
+	client.AccountObjectEscrow:         data.ESCROW,
+	client.AccountObjectOffer:          data.OFFER,
+	client.AccountObjectPaymentChannel: data.PAY_CHANNEL,
+	client.AccountObjectSignerList:     data.SIGNER_LIST,
+	client.AccountObjectState:          data.RIPPLE_STATE,
+	client.AccountObjectTicket:         data.TICKET,
+}
+
+const defaultObjectsLimit = 200
+
+type accountOffersParams struct {
+	Account     string      `json:"account"`
+	Limit       int         `json:"limit"`
+	Marker      string      `json:"marker"`
+	LedgerIndex interface{} `json:"ledger_index"`
+	LedgerHash  string      `json:"ledger_hash"`
+}
+
+type bookOffersParams struct {
+	TakerGets   data.Asset  `json:"taker_gets"`
+	TakerPays   data.Asset  `json:"taker_pays"`
+	Taker       string      `json:"taker"`
+	Limit       int         `json:"limit"`
+	Marker      string      `json:"marker"`
+	LedgerIndex interface{} `json:"ledger_index"`
+	LedgerHash  string      `json:"ledger_hash"`
+}
+
+type accountChannelsParams struct {
+	Account            string      `json:"account"`
+	DestinationAccount string      `json:"destination_account"`
+	Limit              int         `json:"limit"`
+	Marker             string      `json:"marker"`
+	LedgerIndex        interface{} `json:"ledger_index"`
+	LedgerHash         string      `json:"ledger_hash"`
+}
+
+type signForParams struct {
+	Account string                  `json:"account"`
+	Secret  string                  `json:"secret"`
+	TxJson  *types.MultisignPayment `json:"tx_json"`
+}
+
+type submitParams struct {
+	TxBlob string `json:"tx_blob"`
+}
+
+type submitMultisignedParams struct {
+	TxJson json.RawMessage `json:"tx_json"`
+}
+
+type binaryTx struct {
+	TxBlob string `json:"tx_blob"`
+	Meta   string `json:"meta"`
+}
+
+func (this *Server) ledgerClosed(json.RawMessage) (interface{}, error) {
+	ledger := this.ValidatedLedger()
+	return map[string]interface{}{
+		"ledger_hash":  ledger.Hash.String(),
+		"ledger_index": ledger.LedgerSequence,
+	}, nil
+}
+
+func (this *Server) ledger(params json.RawMessage) (interface{}, error) {
+	req := &ledgerParams{}
+	if err := json.Unmarshal(params, req); err != nil {
+		return nil, ErrInvalidParams
+	}
+	ledger, err := this.findLedger(req.LedgerIndex, req.LedgerHash)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]interface{}{
+		"ledger_hash":  ledger.Hash.String(),
+		"ledger_index": ledger.LedgerSequence,
+		"validated":    true,
+	}
+	if !req.Binary {
+		l := *ledger
+		if !req.Transactions || !req.Expand {
+			l.Transactions = nil
+		}
+		res["ledger"] = l
+		return res, nil
+	}
+	_, header, err := data.Raw(ledger)
+	if err != nil {
+		return nil, err
+	}
+	txs := make([]*binaryTx, 0, len(ledger.Transactions))
+	for _, txm := range ledger.Transactions {
+		txBlob, meta, err := txBlobs(txm)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, &binaryTx{
+			TxBlob: strings.ToUpper(hex.EncodeToString(txBlob)),
+			Meta:   strings.ToUpper(hex.EncodeToString(meta)),
+		})
+	}
+	res["ledger"] = map[string]interface{}{
+		"ledger_data":  strings.ToUpper(hex.EncodeToString(header)),
+		"closed":       true,
+		"transactions": txs,
+	}
+	return res, nil
+}
+
+func (this *Server) tx(params json.RawMessage) (interface{}, error) {
+	req := &txParams{}
+	if err := json.Unmarshal(params, req); err != nil {
+		return nil, ErrInvalidParams
+	}
+	hash, err := data.NewHash256(req.Transaction)
+	if err != nil {
+		return nil, ErrInvalidParams
+	}
+	this.lock.Lock()
+	txm, ok := this.txs[*hash]
+	this.lock.Unlock()
+	if !ok {
+		return nil, ErrTxNotFound
+	}
+	if !req.Binary {
+		res, err := toMap(txm)
+		if err != nil {
+			return nil, err
+		}
+		res["validated"] = true
+		return res, nil
+	}
+	txBlob, meta, err := txBlobs(txm)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"tx":           strings.ToUpper(hex.EncodeToString(txBlob)),
+		"meta":         strings.ToUpper(hex.EncodeToString(meta)),
+		"hash":         hash.String(),
+		"ledger_index": txm.LedgerSequence,
+		"validated":    true,
+	}, nil
+}
+
+func (this *Server) accountInfo(params json.RawMessage) (interface{}, error) {
+	req := &accountParams{}
+	if err := json.Unmarshal(params, req); err != nil {
+		return nil, ErrInvalidParams
+	}
+	account, err := data.NewAccountFromAddress(req.Account)
+	if err != nil {
+		return nil, ErrInvalidParams
+	}
+	ledger, err := this.selectLedger(req.LedgerIndex, req.LedgerHash)
+	if err != nil {
+		return nil, err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	root := this.open.Account(*account)
+	if root == nil {
+		return nil, ErrActNotFound
+	}
+	if ledger != nil {
+		return map[string]interface{}{
+			"account_data": root,
+			"ledger_hash":  ledger.Hash.String(),
+			"ledger_index": ledger.LedgerSequence,
+			"validated":    true,
+		}, nil
+	}
+	// the open ledger holds the txs submitted since the last close
+	open := *root
+	sequence := this.open.OpenSequence(*account)
+	open.Sequence = &sequence
+	return &websockets.AccountInfoResult{
+		LedgerSequence: this.ledgers[len(this.ledgers)-1].LedgerSequence + 1,
+		AccountData:    open,
+	}, nil
+}
+
+func (this *Server) accountObjects(params json.RawMessage) (interface{}, error) {
+	req := &accountObjectsParams{}
+	if err := json.Unmarshal(params, req); err != nil {
+		return nil, ErrInvalidParams
+	}
+	account, err := data.NewAccountFromAddress(req.Account)
+	if err != nil {
+		return nil, ErrInvalidParams
+	}
+	objectType, filtered := objectTypes[req.Type]
+	if req.Type != "" && !filtered {
+		return nil, ErrInvalidParams
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultObjectsLimit
+	}
+	ledger, err := this.selectLedger(req.LedgerIndex, req.LedgerHash)
+	if err != nil {
+		return nil, err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.open.Account(*account) == nil {
+		return nil, ErrActNotFound
+	}
+	// the marker is the index of the first object of the next page
+	objects, marker := data.LedgerEntrySlice{}, ""
+	started := req.Marker == ""
+	for _, object := range this.open.Objects(*account) {
+		if !started {
+			started = object.GetLedgerIndex().String() == req.Marker
+		}
+		if !started || (filtered && object.GetLedgerEntryType() != objectType) {
+			continue
+		}
+		if len(objects) == limit {
+			marker = object.GetLedgerIndex().String()
+			break
+		}
+		objects = append(objects, object)
+	}
+	if !started {
+		return nil, ErrInvalidParams
+	}
+	objectsJson, err := objectsJson(objects)
+	if err != nil {
+		return nil, err
+	}
+	res := map[string]interface{}{
+		"account":         req.Account,
+		"account_objects": objectsJson,
+		"limit":           limit,
+	}
+	return this.pageResult(res, marker, ledger), nil
+}
+
+func (this *Server) accountChannels(params json.RawMessage) (interface{}, error) {
+	req := &accountChannelsParams{}
+	if err := json.Unmarshal(params, req); err != nil {
+		return nil, ErrInvalidParams
+	}
+	account, err := data.NewAccountFromAddress(req.Account)
+	if err != nil {
+		return nil, ErrInvalidParams
+	}
+	var destination *data.Account
+	if req.DestinationAccount != "" {
+		if destination, err = data.NewAccountFromAddress(req.DestinationAccount); err != nil {
+			return nil, ErrInvalidParams
+		}
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultObjectsLimit
+	}
+	ledger, err := this.selectLedger(req.LedgerIndex, req.LedgerHash)
+	if err != nil {
+		return nil, err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.open.Account(*account) == nil {
+		return nil, ErrActNotFound
+	}
+	// the marker is the index of the first channel of the next page, as for account_objects
+	channels, marker := []*client.AccountChannel{}, ""
+	started := req.Marker == ""
+	for _, object := range this.open.Objects(*account) {
+		if !started {
+			started = object.GetLedgerIndex().String() == req.Marker
+		}
+		channel, ok := object.(*data.PayChannel)
+		if !started || !ok || (destination != nil && !channel.Destination.Equals(*destination)) {
+			continue
+		}
+		if len(channels) == limit {
+			marker = channel.LedgerIndex.String()
+			break
+		}
+		channels = append(channels, accountChannel(channel))
+	}
+	if !started {
+		return nil, ErrInvalidParams
+	}
+	res := map[string]interface{}{
+		"account":  req.Account,
+		"channels": channels,
+		"limit":    limit,
+	}
+	return this.pageResult(res, marker, ledger), nil
+}
+
+// accountChannel return channel as account_channels reports it
+func accountChannel(channel *data.PayChannel) *client.AccountChannel {
+	res := &client.AccountChannel{
+		ChannelId:          *channel.LedgerIndex,
+		Account:            *channel.Account,
+		DestinationAccount: *channel.Destination,
+		Amount:             uint64(engine.Drops(channel.Amount.Value)),
+		Balance:            uint64(engine.Drops(channel.Balance.Value)),
+		SettleDelay:        *channel.SettleDelay,
+		PublicKeyHex:       *channel.PublicKey,
+	}
+	if publicKey, err := crypto.NewAccountPublicKey(channel.PublicKey.Bytes()); err == nil {
+		res.PublicKey = publicKey.String()
+	}
+	if channel.Expiration != nil {
+		res.Expiration = *channel.Expiration
+	}
+	if channel.CancelAfter != nil {
+		res.CancelAfter = *channel.CancelAfter
+	}
+	if channel.SourceTag != nil {
+		res.SourceTag = *channel.SourceTag
+	}
+	if channel.DestinationTag != nil {
+		res.DestinationTag = *channel.DestinationTag
+	}
+	return res
+}
+
+func (this *Server) accountOffers(params json.RawMessage) (interface{}, error) {
+	req := &accountOffersParams{}
+	if err := json.Unmarshal(params, req); err != nil {
+		return nil, ErrInvalidParams
+	}
+	account, err := data.NewAccountFromAddress(req.Account)
+	if err != nil {
+		return nil, ErrInvalidParams
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultObjectsLimit
+	}
+	ledger, err := this.selectLedger(req.LedgerIndex, req.LedgerHash)
+	if err != nil {
+		return nil, err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.open.Account(*account) == nil {
+		return nil, ErrActNotFound
+	}
+	// the marker is the index of the first offer of the next page, as for account_objects
+	offers, marker := []*client.AccountOffer{}, ""
+	started := req.Marker == ""
+	for _, object := range this.open.Objects(*account) {
+		if !started {
+			started = object.GetLedgerIndex().String() == req.Marker
+		}
+		offer, ok := object.(*data.Offer)
+		if !started || !ok {
+			continue
+		}
+		if len(offers) == limit {
+			marker = offer.LedgerIndex.String()
+			break
+		}
+		quality, err := types.OfferQuality(*offer.TakerPays, *offer.TakerGets)
+		if err != nil {
+			return nil, err
+		}
+		accountOffer := &client.AccountOffer{
+			Flags:     uint32(*offer.Flags),
+			Seq:       *offer.Sequence,
+			TakerGets: *offer.TakerGets,
+			TakerPays: *offer.TakerPays,
+			Quality:   *quality,
+		}
+		if offer.Expiration != nil {
+			accountOffer.Expiration = *offer.Expiration
+		}
+		offers = append(offers, accountOffer)
+	}
+	if !started {
+		return nil, ErrInvalidParams
+	}
+	res := map[string]interface{}{
+		"account": req.Account,
+		"offers":  offers,
+		"limit":   limit,
+	}
+	return this.pageResult(res, marker, ledger), nil
+}
+
+func (this *Server) bookOffers(params json.RawMessage) (interface{}, error) {
+	req := &bookOffersParams{}
+	if err := json.Unmarshal(params, req); err != nil || req.TakerGets.Currency == "" || req.TakerPays.Currency == "" {
+		return nil, ErrInvalidParams
+	}
+	limit := req.Limit
+	if limit <= 0 {
+		limit = defaultObjectsLimit
+	}
+	ledger, err := this.selectLedger(req.LedgerIndex, req.LedgerHash)
+	if err != nil {
+		return nil, err
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	var book []*client.BookOffer
+	for _, offer := range this.open.Offers() {
+		if !req.TakerGets.Matches(offer.TakerGets) || !req.TakerPays.Matches(offer.TakerPays) {
+			continue
+		}
+		quality, err := types.OfferQuality(*offer.TakerPays, *offer.TakerGets)
+		if err != nil {
+			return nil, err
+		}
+		bookOffer := &client.BookOffer{Offer: *offer, Quality: *quality}
+		if offer.TakerGets.IsNative() {
+			bookOffer.OwnerFunds = engine.DropsString(this.open.Account(*offer.Account).Balance)
+		}
+		book = append(book, bookOffer)
+	}
+	// best quality first, the index orders offers of the same quality
+	sort.Slice(book, func(i, j int) bool {
+		if c := book[i].Quality.Cmp(&book[j].Quality); c != 0 {
+			return c < 0
+		}
+		return bytes.Compare(book[i].LedgerIndex[:], book[j].LedgerIndex[:]) < 0
+	})
+	offers, marker := []*client.BookOffer{}, ""
+	started := req.Marker == ""
+	for _, offer := range book {
+		if !started {
+			started = offer.LedgerIndex.String() == req.Marker
+		}
+		if !started {
+			continue
+		}
+		if len(offers) == limit {
+			marker = offer.LedgerIndex.String()
+			break
+		}
+		offers = append(offers, offer)
+	}
+	if !started {
+		return nil, ErrInvalidParams
+	}
+	res := map[string]interface{}{
+		"offers": offers,
+		"limit":  limit,
+	}
+	return this.pageResult(res, marker, ledger), nil
+}
+
+// pageResult add the marker and the ledger of a page to res
+func (this *Server) pageResult(res map[string]interface{}, marker string, ledger *data.Ledger) map[string]interface{} {
+	if marker != "" {
+		res["marker"] = marker
+	}
+	if ledger == nil {
+		res["ledger_current_index"] = this.ledgers[len(this.ledgers)-1].LedgerSequence + 1
+		return res
+	}
+	res["ledger_hash"] = ledger.Hash.String()
+	res["ledger_index"] = ledger.LedgerSequence
+	res["validated"] = true
+	return res
+}
+
+func (this *Server) getFee(json.RawMessage) (interface{}, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.fee, nil
+}
+
+func (this *Server) serverInfo(json.RawMessage) (interface{}, error) {
+	state, err := this.serverStatus()
+	if err != nil {
+		return nil, err
+	}
+	info := &client.ServerInfo{
+		BuildVersion:     state.BuildVersion,
+		CompleteLedgers:  state.CompleteLedgers,
+		LoadFactor:       float64(state.LoadFactor) / float64(state.LoadBase),
+		Peers:            state.Peers,
+		ServerState:      state.ServerState,
+		ValidationQuorum: state.ValidationQuorum,
+	}
+	if v := state.ValidatedLedger; v != nil {
+		info.ValidatedLedger = &client.ValidatedLedgerInfo{
+			BaseFeeXrp:     float64(v.BaseFee) / 1000000,
+			Hash:           v.Hash,
+			ReserveBaseXrp: float64(v.ReserveBase) / 1000000,
+			ReserveIncXrp:  float64(v.ReserveInc) / 1000000,
+			Seq:            v.Seq,
+		}
+	}
+	return map[string]interface{}{"info": info}, nil
+}
+
+func (this *Server) serverState(json.RawMessage) (interface{}, error) {
+	state, err := this.serverStatus()
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{"state": state}, nil
+}
+
+func (this *Server) serverStatus() (*client.ServerState, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	ledger := this.ledgers[len(this.ledgers)-1]
+	state := &client.ServerState{
+		BuildVersion:     "clienttest",
+		CompleteLedgers:  client.CompleteLedgers{{Start: 1, End: ledger.LedgerSequence}},
+		LoadBase:         256,
+		LoadFactor:       256,
+		ServerState:      this.state,
+		ValidationQuorum: 1,
+	}
+	if syncedStates[this.state] {
+		baseFee, _ := strconv.ParseUint(engine.DropsString(&this.fee.Drops.BaseFee), 10, 64)
+		state.ValidatedLedger = &client.ValidatedLedgerState{
+			BaseFee:     baseFee,
+			CloseTime:   ledger.CloseTime.Uint32(),
+			Hash:        ledger.Hash.String(),
+			ReserveBase: this.reserve[0],
+			ReserveInc:  this.reserve[1],
+			Seq:         ledger.LedgerSequence,
+		}
+	}
+	return state, nil
+}
+
+var syncedStates = map[string]bool{"full": true, "validating": true, "proposing": true}
+
+func (this *Server) signFor(params json.RawMessage) (interface{}, error) {
+	req := &signForParams{}
+	if err := json.Unmarshal(params, req); err != nil || req.TxJson == nil {
+		return nil, ErrInvalidParams
+	}
+	signer, err := types.ImportAccount(req.Secret)
+	if err != nil {
+		return nil, ErrBadSecret
+	}
+	if signer.Account.String() != req.Account {
+		return nil, ErrBadSecret
+	}
+	payment, err := toPayment(req.TxJson)
+	if err != nil {
+		return nil, &RpcError{Name: "invalidTransaction", Code: 74, Message: err.Error()}
+	}
+	// the library signs the Signers field too, so sign the tx without the signatures collected so far
+	unsigned := unsignedPayment(payment)
+	var sequence uint32
+	if err := data.MultiSign(unsigned, signer.Key, &sequence, signer.Account); err != nil {
+		return nil, err
+	}
+	payment.Signers = append(payment.Signers, unsigned.Signers...)
+	txJson, txBlob, err := fromPayment(payment)
+	if err != nil {
+		return nil, err
+	}
+	return map[string]interface{}{
+		"tx_json": txJson,
+		"tx_blob": txBlob,
+	}, nil
+}
+
+func (this *Server) submitMultisigned(params json.RawMessage) (interface{}, error) {
+	req := &submitMultisignedParams{}
+	if err := json.Unmarshal(params, req); err != nil || len(req.TxJson) == 0 {
+		return nil, ErrInvalidParams
+	}
+	header := &struct {
+		TransactionType string
+		TicketSequence  *uint32
+	}{}
+	if err := json.Unmarshal(req.TxJson, header); err != nil {
+		return nil, ErrInvalidParams
+	}
+	if header.TransactionType != "Payment" || header.TicketSequence != nil {
+		return this.submitMultisignedTx(req.TxJson)
+	}
+	txJson := &types.MultisignPayment{}
+	if err := json.Unmarshal(req.TxJson, txJson); err != nil {
+		return nil, ErrInvalidParams
+	}
+	payment, err := toPayment(txJson)
+	if err != nil {
+		return nil, &RpcError{Name: "invalidTransaction", Code: 74, Message: err.Error()}
+	}
+	if err := checkMultiSignatures(payment); err != nil {
+		return nil, err
+	}
+	hash, _, err := data.Raw(payment)
+	if err != nil {
+		return nil, err
+	}
+	*payment.GetHash() = hash
+	txJson, txBlob, err := fromPayment(payment)
+	if err != nil {
+		return nil, err
+	}
+	tx, err := toMap(txJson)
+	if err != nil {
+		return nil, err
+	}
+	tx["hash"] = hash.String()
+	engineResult := this.submitMultisignedResult(payment)
+	return submitResponse(engineResult, tx, txBlob), nil
+}
+
+// submitMultisignedTx submit a multi-signed tx of any type other than a payment using a sequence
+func (this *Server) submitMultisignedTx(txJson json.RawMessage) (interface{}, error) {
+	tx, err := types.UnmarshalTransaction(txJson)
+	if err != nil {
+		return nil, &RpcError{Name: "invalidTransaction", Code: 74, Message: err.Error()}
+	}
+	if err := checkMultiSignatures(tx); err != nil {
+		return nil, err
+	}
+	types.AddSigners(tx)
+	hash, raw, err := data.Raw(tx)
+	if err != nil {
+		return nil, err
+	}
+	*tx.GetHash() = hash
+	res, err := toMap(tx)
+	if err != nil {
+		return nil, err
+	}
+	res["hash"] = hash.String()
+	engineResult := this.submitMultisignedResult(tx)
+	return submitResponse(engineResult, res, strings.ToUpper(hex.EncodeToString(raw))), nil
+}
+
+// checkMultiSignatures check the signature of every signer of tx over the tx without signers
+func checkMultiSignatures(tx data.Transaction) error {
+	base := tx.GetBase()
+	if len(base.Signers) == 0 {
+		return &RpcError{Name: "invalidParams", Code: 31, Message: "tx_json.Signers array may not be empty."}
+	}
+	signers := base.Signers
+	base.Signers = make([]data.Signer, 0)
+	defer func() { base.Signers = signers }()
+	for _, s := range signers {
+		if s.Signer.SigningPubKey == nil || s.Signer.TxnSignature == nil {
+			return &RpcError{Name: "invalidTransaction", Code: 74, Message: "fails local checks: Missing signature."}
+		}
+		ok, err := data.CheckMultiSignature(tx, s.Signer.Account, s.Signer.SigningPubKey.Bytes(), s.Signer.TxnSignature.Bytes())
+		if err != nil || !ok {
+			return &RpcError{Name: "invalidTransaction", Code: 74, Message: "fails local checks: Invalid signature."}
+		}
+	}
+	return nil
+}
+
+// submitMultisignedResult submit tx, whose signatures are checked, to the open ledger
+func (this *Server) submitMultisignedResult(tx data.Transaction) data.TransactionResult {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.open.SubmitMultisigned(tx)
+}
+
+// submitTx accept single-signed txs signed with the master key, the regular key is not supported
+func (this *Server) submitTx(params json.RawMessage) (interface{}, error) {
+	req := &submitParams{}
+	if err := json.Unmarshal(params, req); err != nil || req.TxBlob == "" {
+		return nil, ErrInvalidParams
+	}
+	tx, err := types.DeserializeTransaction(req.TxBlob)
+	if err != nil {
+		return nil, &RpcError{Name: "invalidTransaction", Code: 74, Message: err.Error()}
+	}
+	base := tx.GetBase()
+	if base.SigningPubKey == nil || base.TxnSignature == nil {
+		return nil, &RpcError{Name: "invalidTransaction", Code: 74, Message: "fails local checks: Missing signature."}
+	}
+	if ok, err := data.CheckSignature(tx); err != nil || !ok {
+		return nil, &RpcError{Name: "invalidTransaction", Code: 74, Message: "fails local checks: Invalid signature."}
+	}
+	var signer data.Account
+	copy(signer[:], crypto.Sha256RipeMD160(base.SigningPubKey.Bytes()))
+	this.lock.Lock()
+	engineResult := this.open.SubmitSigned(tx, signer)
+	this.lock.Unlock()
+	return submitResponse(engineResult, map[string]interface{}{"hash": base.Hash.String()}, strings.ToUpper(req.TxBlob)), nil
+}
+
+// objectsJson return the json of objects, the entries of a signer list are wrapped as rippled does
+func objectsJson(objects data.LedgerEntrySlice) ([]interface{}, error) {
+	res := make([]interface{}, 0, len(objects))
+	for _, object := range objects {
+		signerList, ok := object.(*data.SignerList)
+		if !ok {
+			res = append(res, object)
+			continue
+		}
+		m, err := toMap(signerList)
+		if err != nil {
+			return nil, err
+		}
+		entries := make([]types.SignerEntry, 0, len(signerList.SignerEntries))
+		for _, entry := range signerList.SignerEntries {
+			entries = append(entries, types.NewSignerEntry(*entry.Account, *entry.SignerWeight))
+		}
+		m["SignerEntries"] = entries
+		res = append(res, m)
+	}
+	return res, nil
+}
+
+// selectLedger return the closed ledger selected by index or hash, nil for the current ledger. The
+// mock keeps no history, an object read from a closed ledger has its latest state
+func (this *Server) selectLedger(index interface{}, hash string) (*data.Ledger, error) {
+	if (index == nil || index == "current") && hash == "" {
+		return nil, nil
+	}
+	return this.findLedger(index, hash)
+}
+
+// findLedger return the ledger of hash if given, else the ledger of index. The mock closes ledgers
+// validated, so validated, closed and current all select the last ledger
+func (this *Server) findLedger(index interface{}, hash string) (*data.Ledger, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if hash != "" {
+		h, err := data.NewHash256(hash)
+		if err != nil {
+			return nil, ErrInvalidParams
+		}
+		for _, ledger := range this.ledgers {
+			if ledger.Hash == *h {
+				return ledger, nil
+			}
+		}
+		return nil, ErrLedgerNotFound
+	}
+	var seq uint32
+	switch v := index.(type) {
+	case nil:
+		seq = uint32(len(this.ledgers))
+	case float64:
+		seq = uint32(v)
+	case string:
+		switch v {
+		case "validated", "closed", "current":
+			seq = uint32(len(this.ledgers))
+		default:
+			parsed, err := strconv.ParseUint(v, 10, 32)
+			if err != nil {
+				return nil, ErrInvalidParams
+			}
+			seq = uint32(parsed)
+		}
+	default:
+		return nil, ErrInvalidParams
+	}
+	ledger := this.ledgerByIndex(seq)
+	if ledger == nil {
+		return nil, ErrLedgerNotFound
+	}
+	return ledger, nil
+}
+
+// toPayment convert the tx_json of the multi-sign rpc to the library payment
+func toPayment(txJson *types.MultisignPayment) (*data.Payment, error) {
+	from, err := data.NewAccountFromAddress(txJson.Account)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Account %s", txJson.Account)
+	}
+	to, err := data.NewAccountFromAddress(txJson.Destination)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Destination %s", txJson.Destination)
+	}
+	amount, err := data.NewAmount(txJson.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Amount %s", txJson.Amount)
+	}
+	fee, err := data.NewValue(txJson.Fee, true)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Fee %s", txJson.Fee)
+	}
+	payment := types.GeneratePayment(*from, *to, *amount, *fee, txJson.Sequence)
+	for _, m := range txJson.Memos {
+		memo := data.Memo{}
+		memo.Memo.MemoType, _ = hex.DecodeString(m.Memo.MemoType)
+		memo.Memo.MemoData, _ = hex.DecodeString(m.Memo.MemoData)
+		memo.Memo.MemoFormat, _ = hex.DecodeString(m.Memo.MemoFormat)
+		payment.Memos = append(payment.Memos, memo)
+	}
+	payment.InitialiseForMultiSigning()
+	for _, s := range txJson.Signers {
+		signer := data.Signer{}
+		account, err := data.NewAccountFromAddress(s.Signer.Account)
+		if err != nil {
+			return nil, fmt.Errorf("invalid Signer %s", s.Signer.Account)
+		}
+		signer.Signer.Account = *account
+		pub, err := hex.DecodeString(s.Signer.SigningPubKey)
+		if err != nil {
+			return nil, fmt.Errorf("invalid SigningPubKey %s", s.Signer.SigningPubKey)
+		}
+		signer.Signer.SigningPubKey = new(data.PublicKey)
+		copy(signer.Signer.SigningPubKey[:], pub)
+		signature, err := hex.DecodeString(s.Signer.TxnSignature)
+		if err != nil {
+			return nil, fmt.Errorf("invalid TxnSignature %s", s.Signer.TxnSignature)
+		}
+		signer.Signer.TxnSignature = new(data.VariableLength)
+		*signer.Signer.TxnSignature = data.VariableLength(signature)
+		payment.Signers = append(payment.Signers, signer)
+	}
+	return payment, nil
+}
+
+func unsignedPayment(payment *data.Payment) *data.Payment {
+	unsigned := *payment
+	unsigned.Signers = make([]data.Signer, 0)
+	return &unsigned
+}
+
+// fromPayment return the tx_json and tx_blob of a multi-signed payment, signers are sorted as rippled does
+func fromPayment(payment *data.Payment) (*types.MultisignPayment, string, error) {
+	sort.Slice(payment.Signers, func(i, j int) bool {
+		return payment.Signers[i].Signer.Account.Less(payment.Signers[j].Signer.Account)
+	})
+	_, raw, err := data.Raw(payment)
+	if err != nil {
+		return nil, "", err
+	}
+	txJson := &types.MultisignPayment{
+		TransactionType: payment.GetTransactionType().String(),
+		Account:         payment.Account.String(),
+		Destination:     payment.Destination.String(),
+		Amount:          engine.DropsString(payment.Amount.Value),
+		Fee:             engine.DropsString(&payment.Fee),
+		Sequence:        payment.Sequence,
+	}
+	if !payment.Amount.IsNative() {
+		txJson.Amount = payment.Amount.String()
+	}
+	for _, m := range payment.Memos {
+		memo := types.Memo{}
+		memo.Memo.MemoType = strings.ToUpper(hex.EncodeToString(m.Memo.MemoType))
+		memo.Memo.MemoData = strings.ToUpper(hex.EncodeToString(m.Memo.MemoData))
+		memo.Memo.MemoFormat = strings.ToUpper(hex.EncodeToString(m.Memo.MemoFormat))
+		txJson.Memos = append(txJson.Memos, memo)
+	}
+	for _, s := range payment.Signers {
+		signer := &types.Signer{}
+		signer.Signer.Account = s.Signer.Account.String()
+		signer.Signer.SigningPubKey = strings.ToUpper(hex.EncodeToString(s.Signer.SigningPubKey.Bytes()))
+		signer.Signer.TxnSignature = strings.ToUpper(hex.EncodeToString(s.Signer.TxnSignature.Bytes()))
+		txJson.Signers = append(txJson.Signers, signer)
+	}
+	return txJson, strings.ToUpper(hex.EncodeToString(raw)), nil
+}
+
+// txBlobs split the transaction node of txm into the tx blob and the metadata blob
+func txBlobs(txm *data.TransactionWithMetaData) ([]byte, []byte, error) {
+	_, raw, err := data.Raw(txm)
+	if err != nil {
+		return nil, nil, err
+	}
+	r := bytes.NewReader(raw)
+	txBlob, err := readVariableLength(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	meta, err := readVariableLength(r)
+	if err != nil {
+		return nil, nil, err
+	}
+	return txBlob, meta, nil
+}
+
+func readVariableLength(r *bytes.Reader) ([]byte, error) {
+	b1, err := r.ReadByte()
+	if err != nil {
+		return nil, err
+	}
+	length := int(b1)
+	if b1 > 192 {
+		b2, err := r.ReadByte()
+		if err != nil {
+			return nil, err
+		}
+		if b1 <= 240 {
+			length = 193 + (int(b1)-193)*256 + int(b2)
+		} else {
+			b3, err := r.ReadByte()
+			if err != nil {
+				return nil, err
+			}
+			length = 12481 + (int(b1)-241)*65536 + int(b2)*256 + int(b3)
+		}
+	}
+	value := make([]byte, length)
+	if _, err := r.Read(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
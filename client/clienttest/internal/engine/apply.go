@@ -0,0 +1,148 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Close apply the queued txs at now, the close time of the last closed ledger, and return them with
+// their metadata in apply order
+func (this *Ledger) Close(now uint32) data.TransactionSlice {
+	this.now = now
+	var txs data.TransactionSlice
+	for i, tx := range this.pending {
+		txm := &data.TransactionWithMetaData{Transaction: tx}
+		txm.MetaData.TransactionIndex = uint32(i)
+		before := this.snapshot()
+		txm.MetaData.TransactionResult = this.apply(tx)
+		txm.MetaData.AffectedNodes = this.affectedNodes(before)
+		txs = append(txs, txm)
+	}
+	this.pending = nil
+	return txs
+}
+
+func (this *Ledger) apply(tx data.Transaction) data.TransactionResult {
+	base := tx.GetBase()
+	root := this.accounts[base.Account]
+	if ticket, ok := types.GetTicketSequence(tx); ok {
+		this.RemoveObject(base.Account, types.GetTicketIndex(base.Account, ticket))
+	} else {
+		*root.Sequence++
+	}
+	balance := Drops(root.Balance) - Drops(&base.Fee)
+	root.Balance, _ = data.NewNativeValue(balance)
+	tx = types.Unticketed(tx)
+	switch v := tx.(type) {
+	case *types.SignerListSet:
+		return this.applySignerListSet(v)
+	case *data.AccountSet:
+		return this.applyAccountSet(v)
+	case *data.TicketCreate:
+		return this.applyTicketCreate(v)
+	case *types.EscrowCreate:
+		return this.applyEscrowCreate(v)
+	case *types.EscrowFinish:
+		return this.applyEscrowFinish(v)
+	case *data.EscrowCancel:
+		return this.applyEscrowCancel(v)
+	case *data.PaymentChannelCreate:
+		return this.applyPaymentChannelCreate(v)
+	case *data.PaymentChannelFund:
+		return this.applyPaymentChannelFund(v)
+	case *data.PaymentChannelClaim:
+		return this.applyPaymentChannelClaim(v)
+	case *data.CheckCreate:
+		return this.applyCheckCreate(v)
+	case *data.CheckCash:
+		return this.applyCheckCash(v)
+	case *data.CheckCancel:
+		return this.applyCheckCancel(v)
+	case *data.OfferCreate:
+		return this.applyOfferCreate(v)
+	case *data.OfferCancel:
+		this.RemoveObject(v.Account, types.GetOfferIndex(v.Account, v.OfferSequence))
+		return Result("tesSUCCESS")
+	}
+	payment, ok := tx.(*data.Payment)
+	if !ok || !payment.Amount.IsNative() {
+		return Result("tesSUCCESS")
+	}
+	amount := Drops(payment.Amount.Value)
+	if balance < amount {
+		return Result("tecUNFUNDED_PAYMENT")
+	}
+	root.Balance, _ = data.NewNativeValue(balance - amount)
+	destination, ok := this.accounts[payment.Destination]
+	if !ok {
+		destination = this.NewAccount(payment.Destination)
+	}
+	destination.Balance, _ = data.NewNativeValue(Drops(destination.Balance) + amount)
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applySignerListSet(tx *types.SignerListSet) data.TransactionResult {
+	index := types.GetSignerListIndex(tx.Account)
+	this.RemoveObject(tx.Account, index)
+	if tx.SignerQuorum == 0 {
+		return Result("tesSUCCESS")
+	}
+	signerList := &data.SignerList{SignerQuorum: &tx.SignerQuorum}
+	signerList.LedgerEntryType = data.SIGNER_LIST
+	signerList.LedgerIndex = &index
+	for _, entry := range tx.SignerEntries {
+		account, weight := entry.SignerEntry.Account, entry.SignerEntry.SignerWeight
+		signerList.SignerEntries = append(signerList.SignerEntries, data.SignerEntry{Account: &account, SignerWeight: &weight})
+	}
+	this.AddObject(tx.Account, signerList)
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applyAccountSet(tx *data.AccountSet) data.TransactionResult {
+	root := this.accounts[tx.Account]
+	switch {
+	case tx.SetFlag != nil && *tx.SetFlag == types.AsfDisableMaster:
+		if this.signerList(tx.Account) == nil && root.RegularKey == nil {
+			return Result("tecNO_ALTERNATIVE_KEY")
+		}
+		*root.Flags |= data.LsDisableMaster
+	case tx.ClearFlag != nil && *tx.ClearFlag == types.AsfDisableMaster:
+		*root.Flags &^= data.LsDisableMaster
+	}
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applyTicketCreate(tx *data.TicketCreate) data.TransactionResult {
+	root := this.accounts[tx.Account]
+	count := *tx.TicketCount
+	if uint32(len(this.tickets(tx.Account)))+count > types.MaxTickets {
+		return Result("tecDIR_FULL")
+	}
+	for i := uint32(0); i < count; i++ {
+		sequence := *root.Sequence + i
+		ticket := &data.Ticket{Account: &tx.Account, TicketSequence: &sequence}
+		ticket.LedgerEntryType = data.TICKET
+		index := types.GetTicketIndex(tx.Account, sequence)
+		ticket.LedgerIndex = &index
+		this.AddObject(tx.Account, ticket)
+	}
+	*root.Sequence += count
+	return Result("tesSUCCESS")
+}
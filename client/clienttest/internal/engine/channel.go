@@ -0,0 +1,171 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+func (this *Ledger) applyPaymentChannelCreate(tx *data.PaymentChannelCreate) data.TransactionResult {
+	root := this.accounts[tx.Account]
+	switch {
+	case tx.CancelAfter != nil && *tx.CancelAfter <= this.now:
+		return Result("tecEXPIRED")
+	case this.accounts[tx.Destination] == nil:
+		return Result("tecNO_DST")
+	}
+	amount := Drops(tx.Amount.Value)
+	if Drops(root.Balance) < amount {
+		return Result("tecUNFUNDED")
+	}
+	root.Balance, _ = data.NewNativeValue(Drops(root.Balance) - amount)
+	balance, _ := data.NewNativeValue(0)
+	channel := &data.PayChannel{
+		Account:        &tx.Account,
+		Destination:    &tx.Destination,
+		Amount:         &tx.Amount,
+		Balance:        &data.Amount{Value: balance},
+		PublicKey:      &tx.PublicKey,
+		SettleDelay:    &tx.SettleDelay,
+		CancelAfter:    tx.CancelAfter,
+		DestinationTag: tx.DestinationTag,
+	}
+	channel.LedgerEntryType = data.PAY_CHANNEL
+	index := types.GetPayChannelIndex(tx.Account, tx.Destination, tx.Sequence)
+	channel.LedgerIndex = &index
+	this.AddObject(tx.Account, channel)
+	return Result("tesSUCCESS")
+}
+
+// payChannel return the channel of index, which is in the owner directory of its source
+func (this *Ledger) payChannel(index data.Hash256) *data.PayChannel {
+	channel, _ := this.findObject(index).(*data.PayChannel)
+	return channel
+}
+
+// payChannelExpired return whether the channel is past its CancelAfter or Expiration, it is then
+// closed by the next tx on it
+func (this *Ledger) payChannelExpired(channel *data.PayChannel) bool {
+	return channel.CancelAfter != nil && this.now >= *channel.CancelAfter || channel.Expiration != nil && this.now >= *channel.Expiration
+}
+
+// updatePayChannel replace channel with a copy to change, as objects are not changed in place
+func (this *Ledger) updatePayChannel(channel *data.PayChannel) *data.PayChannel {
+	updated := *channel
+	objects := this.objects[*channel.Account]
+	for i, object := range objects {
+		if object == channel {
+			objects[i] = &updated
+		}
+	}
+	return &updated
+}
+
+// closePayChannel return the XRP left in channel to its source and remove it
+func (this *Ledger) closePayChannel(channel *data.PayChannel) {
+	source := this.accounts[*channel.Account]
+	left := Drops(channel.Amount.Value) - Drops(channel.Balance.Value)
+	source.Balance, _ = data.NewNativeValue(Drops(source.Balance) + left)
+	this.RemoveObject(*channel.Account, *channel.LedgerIndex)
+}
+
+func (this *Ledger) applyPaymentChannelFund(tx *data.PaymentChannelFund) data.TransactionResult {
+	channel := this.payChannel(tx.Channel)
+	switch {
+	case channel == nil:
+		return Result("tecNO_ENTRY")
+	case !channel.Account.Equals(tx.Account):
+		return Result("tecNO_PERMISSION")
+	case this.payChannelExpired(channel):
+		this.closePayChannel(channel)
+		return Result("tesSUCCESS")
+	case tx.Expiration != nil && *tx.Expiration < this.now+*channel.SettleDelay:
+		return Result("temBAD_EXPIRATION")
+	}
+	root := this.accounts[tx.Account]
+	amount := Drops(tx.Amount.Value)
+	if Drops(root.Balance) < amount {
+		return Result("tecUNFUNDED")
+	}
+	root.Balance, _ = data.NewNativeValue(Drops(root.Balance) - amount)
+	channel = this.updatePayChannel(channel)
+	total, _ := data.NewNativeValue(Drops(channel.Amount.Value) + amount)
+	channel.Amount = &data.Amount{Value: total}
+	if tx.Expiration != nil {
+		channel.Expiration = tx.Expiration
+	}
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applyPaymentChannelClaim(tx *data.PaymentChannelClaim) data.TransactionResult {
+	channel := this.payChannel(tx.Channel)
+	if channel == nil {
+		return Result("tecNO_TARGET")
+	}
+	var flags data.TransactionFlag
+	if tx.Flags != nil {
+		flags = *tx.Flags
+	}
+	isSource, isDestination := channel.Account.Equals(tx.Account), channel.Destination.Equals(tx.Account)
+	switch {
+	case !isSource && !isDestination, flags&data.TxRenew != 0 && !isSource:
+		return Result("tecNO_PERMISSION")
+	case this.payChannelExpired(channel):
+		this.closePayChannel(channel)
+		return Result("tesSUCCESS")
+	}
+	if tx.Balance != nil {
+		requested := Drops(tx.Balance.Value)
+		if isDestination && tx.Signature == nil {
+			return Result("temBAD_SIGNATURE")
+		}
+		if tx.Signature != nil {
+			if tx.Amount == nil || tx.PublicKey == nil || *tx.PublicKey != *channel.PublicKey || requested > Drops(tx.Amount.Value) ||
+				types.VerifyChannelClaim(tx.Channel, uint64(Drops(tx.Amount.Value)), tx.Signature.Bytes(), tx.PublicKey.Bytes()) != nil {
+				return Result("temBAD_SIGNATURE")
+			}
+		}
+		delivered := Drops(channel.Balance.Value)
+		if requested > Drops(channel.Amount.Value) || requested <= delivered {
+			return Result("tecUNFUNDED_PAYMENT")
+		}
+		destination := this.accounts[*channel.Destination]
+		destination.Balance, _ = data.NewNativeValue(Drops(destination.Balance) + requested - delivered)
+		balance, _ := data.NewNativeValue(requested)
+		channel = this.updatePayChannel(channel)
+		channel.Balance = &data.Amount{Value: balance}
+	}
+	if flags&(data.TxRenew|data.TxClose) != 0 {
+		channel = this.updatePayChannel(channel)
+	}
+	if flags&data.TxRenew != 0 {
+		channel.Expiration = nil
+	}
+	if flags&data.TxClose != 0 {
+		if isDestination || Drops(channel.Balance.Value) == Drops(channel.Amount.Value) {
+			this.closePayChannel(channel)
+			return Result("tesSUCCESS")
+		}
+		expiration := this.now + *channel.SettleDelay
+		if channel.Expiration == nil || *channel.Expiration > expiration {
+			channel.Expiration = &expiration
+		}
+	}
+	return Result("tesSUCCESS")
+}
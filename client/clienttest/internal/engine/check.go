@@ -0,0 +1,115 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+func (this *Ledger) applyCheckCreate(tx *data.CheckCreate) data.TransactionResult {
+	switch {
+	case tx.Expiration != nil && *tx.Expiration <= this.now:
+		return Result("tecEXPIRED")
+	case this.accounts[tx.Destination] == nil:
+		return Result("tecNO_DST")
+	}
+	check := &data.Check{
+		Account:        &tx.Account,
+		Destination:    &tx.Destination,
+		SendMax:        &tx.SendMax,
+		Sequence:       &tx.Sequence,
+		DestinationTag: tx.DestinationTag,
+		Expiration:     tx.Expiration,
+		InvoiceID:      tx.InvoiceID,
+	}
+	check.LedgerEntryType = data.CHECK
+	index := types.GetCheckIndex(tx.Account, tx.Sequence)
+	check.LedgerIndex = &index
+	this.AddObject(tx.Account, check)
+	this.linkObject(tx.Destination, check)
+	return Result("tesSUCCESS")
+}
+
+// removeCheck remove check from the directories of its source and destination
+func (this *Ledger) removeCheck(check *data.Check) {
+	this.RemoveObject(*check.Account, *check.LedgerIndex)
+	this.unlinkObject(*check.Destination, *check.LedgerIndex)
+}
+
+func (this *Ledger) checkExpired(check *data.Check) bool {
+	return check.Expiration != nil && this.now >= *check.Expiration
+}
+
+// findCheck return the check of id whoever owns it, nil if there is none
+func (this *Ledger) findCheck(id data.Hash256) *data.Check {
+	check, _ := this.findObject(id).(*data.Check)
+	return check
+}
+
+// applyCheckCash cash a check, the engine only moves XRP and has no trust lines for issued currencies
+func (this *Ledger) applyCheckCash(tx *data.CheckCash) data.TransactionResult {
+	check := this.findCheck(tx.CheckID)
+	switch {
+	case check == nil:
+		return Result("tecNO_ENTRY")
+	case !check.Destination.Equals(tx.Account):
+		return Result("tecNO_PERMISSION")
+	case this.checkExpired(check):
+		return Result("tecEXPIRED")
+	case !check.SendMax.IsNative():
+		return Result("tecNO_LINE")
+	}
+	source := this.accounts[*check.Account]
+	available, sendMax := Drops(source.Balance), Drops(check.SendMax.Value)
+	var amount int64
+	switch {
+	case tx.Amount != nil:
+		amount = Drops(tx.Amount.Value)
+		if amount > sendMax || amount > available {
+			return Result("tecPATH_PARTIAL")
+		}
+	case tx.DeliverMin != nil:
+		amount = sendMax
+		if amount > available {
+			amount = available
+		}
+		if amount < Drops(tx.DeliverMin.Value) {
+			return Result("tecPATH_PARTIAL")
+		}
+	default:
+		return Result("temMALFORMED")
+	}
+	source.Balance, _ = data.NewNativeValue(available - amount)
+	destination := this.accounts[tx.Account]
+	destination.Balance, _ = data.NewNativeValue(Drops(destination.Balance) + amount)
+	this.removeCheck(check)
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applyCheckCancel(tx *data.CheckCancel) data.TransactionResult {
+	check := this.findCheck(tx.CheckID)
+	switch {
+	case check == nil:
+		return Result("tecNO_ENTRY")
+	case !check.Account.Equals(tx.Account) && !check.Destination.Equals(tx.Account) && !this.checkExpired(check):
+		return Result("tecNO_PERMISSION")
+	}
+	this.removeCheck(check)
+	return Result("tesSUCCESS")
+}
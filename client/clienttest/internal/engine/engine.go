@@ -0,0 +1,208 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package engine applies transactions to the open ledger of the clienttest mock server. It keeps the
+// account roots and owner directories and simulates the subset of the rippled rules the client tests
+// rely on: sequences and tickets, signer lists, escrows, payment channels, checks and offers. It is not a
+// ledger implementation, anything outside that subset succeeds without changing the state
+package engine
+
+import (
+	"strconv"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+const defaultAccountSeqNum = 1
+
+// mockResults are the results rippled returns which the library does not know, under codes the library
+// does not use. They are engine results of a submit only, they never reach a ledger
+var mockResults = map[data.TransactionResult][2]string{
+	-150: {"tefBAD_SIGNATURE", "A signature is provided for a non-signer."},
+	-149: {"tefBAD_QUORUM", "Signatures provided do not meet the quorum."},
+}
+
+// Result return the result of token, a token neither the library nor the engine knows is reported as
+// tefINTERNAL so that the test asserting it fails instead of the whole test binary
+func Result(token string) data.TransactionResult {
+	var r data.TransactionResult
+	if err := r.UnmarshalText([]byte(token)); err == nil {
+		return r
+	}
+	for r, names := range mockResults {
+		if names[0] == token {
+			return r
+		}
+	}
+	r.UnmarshalText([]byte("tefINTERNAL"))
+	return r
+}
+
+// ResultNames return the token and the message of r
+func ResultNames(r data.TransactionResult) (string, string) {
+	if names, ok := mockResults[r]; ok {
+		return names[0], names[1]
+	}
+	return r.String(), r.Human()
+}
+
+// Ledger is the open ledger: the account roots, the owner directories and the txs queued for the next
+// close. It is not safe for concurrent use
+type Ledger struct {
+	accounts map[data.Account]*data.AccountRoot
+	objects  map[data.Account]data.LedgerEntrySlice
+	pending  []data.Transaction
+	now      uint32 //now is the close time of the last closed ledger, which the txs are applied at
+}
+
+// NewLedger return an open ledger without accounts
+func NewLedger() *Ledger {
+	return &Ledger{
+		accounts: make(map[data.Account]*data.AccountRoot),
+		objects:  make(map[data.Account]data.LedgerEntrySlice),
+	}
+}
+
+// Account return the account root of account, nil if it does not exist
+func (this *Ledger) Account(account data.Account) *data.AccountRoot {
+	return this.accounts[account]
+}
+
+// SetAccount create or replace an account root
+func (this *Ledger) SetAccount(root *data.AccountRoot) {
+	this.accounts[*root.Account] = root
+}
+
+// NewAccount create or reset the account root of account, with no XRP
+func (this *Ledger) NewAccount(account data.Account) *data.AccountRoot {
+	root := &data.AccountRoot{}
+	root.LedgerEntryType = data.ACCOUNT_ROOT
+	root.Account = &account
+	flags := data.LedgerEntryFlag(0)
+	root.Flags = &flags
+	sequence, ownerCount := uint32(defaultAccountSeqNum), uint32(0)
+	root.Sequence = &sequence
+	root.OwnerCount = &ownerCount
+	root.Balance, _ = data.NewNativeValue(0)
+	index, _ := data.GetAccountRootIndex(account)
+	root.LedgerIndex = index
+	this.accounts[account] = root
+	return root
+}
+
+// Objects return the owner directory of owner
+func (this *Ledger) Objects(owner data.Account) data.LedgerEntrySlice {
+	return this.objects[owner]
+}
+
+// Offers return the offers of every account
+func (this *Ledger) Offers() []*data.Offer {
+	var offers []*data.Offer
+	for _, objects := range this.objects {
+		for _, object := range objects {
+			if offer, ok := object.(*data.Offer); ok {
+				offers = append(offers, offer)
+			}
+		}
+	}
+	return offers
+}
+
+// AddObject add object to the owner directory of owner and count it in the owner reserve, the account
+// root of owner must exist
+func (this *Ledger) AddObject(owner data.Account, object data.LedgerEntry) {
+	this.objects[owner] = append(this.objects[owner], object)
+	*this.accounts[owner].OwnerCount++
+}
+
+// RemoveObject remove the object of index from the owner directory of owner
+func (this *Ledger) RemoveObject(owner data.Account, index data.Hash256) bool {
+	objects := this.objects[owner]
+	for i, object := range objects {
+		if *object.GetLedgerIndex() == index {
+			this.objects[owner] = append(objects[:i:i], objects[i+1:]...)
+			*this.accounts[owner].OwnerCount--
+			return true
+		}
+	}
+	return false
+}
+
+// linkObject add object to the owner directory of account without counting it in its owner reserve, as
+// a check is in the directory of its destination as well
+func (this *Ledger) linkObject(account data.Account, object data.LedgerEntry) {
+	this.objects[account] = append(this.objects[account], object)
+}
+
+func (this *Ledger) unlinkObject(account data.Account, index data.Hash256) {
+	objects := this.objects[account]
+	for i, object := range objects {
+		if *object.GetLedgerIndex() == index {
+			this.objects[account] = append(objects[:i:i], objects[i+1:]...)
+			return
+		}
+	}
+}
+
+func (this *Ledger) object(owner data.Account, index data.Hash256) data.LedgerEntry {
+	for _, object := range this.objects[owner] {
+		if *object.GetLedgerIndex() == index {
+			return object
+		}
+	}
+	return nil
+}
+
+// findObject return the object of index whoever owns it, nil if there is none
+func (this *Ledger) findObject(index data.Hash256) data.LedgerEntry {
+	for _, objects := range this.objects {
+		for _, object := range objects {
+			if *object.GetLedgerIndex() == index {
+				return object
+			}
+		}
+	}
+	return nil
+}
+
+func (this *Ledger) signerList(owner data.Account) *data.SignerList {
+	signerList, _ := this.object(owner, types.GetSignerListIndex(owner)).(*data.SignerList)
+	return signerList
+}
+
+func (this *Ledger) tickets(owner data.Account) []*data.Ticket {
+	var tickets []*data.Ticket
+	for _, object := range this.objects[owner] {
+		if ticket, ok := object.(*data.Ticket); ok {
+			tickets = append(tickets, ticket)
+		}
+	}
+	return tickets
+}
+
+// Drops return the drops of the native value v
+func Drops(v *data.Value) int64 {
+	n, _ := strconv.ParseInt(DropsString(v), 10, 64)
+	return n
+}
+
+// DropsString return the drops of the native value v in decimal
+func DropsString(v *data.Value) string {
+	text, _ := v.MarshalText()
+	return string(text)
+}
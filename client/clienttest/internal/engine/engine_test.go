@@ -0,0 +1,308 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+const genesis = 700000000
+
+var alice, bob, carol = data.Account{1}, data.Account{2}, data.Account{3}
+
+func newLedger(t *testing.T) *Ledger {
+	l := NewLedger()
+	for _, account := range []data.Account{alice, bob, carol} {
+		root := l.NewAccount(account)
+		root.Balance = value(t, 100000000)
+	}
+	return l
+}
+
+func value(t *testing.T, drops int64) *data.Value {
+	v, err := data.NewNativeValue(drops)
+	assert.Nil(t, err)
+	return v
+}
+
+func xrp(t *testing.T, drops int64) data.Amount {
+	return data.Amount{Value: value(t, drops)}
+}
+
+func fee(t *testing.T) data.Value {
+	return *value(t, 10)
+}
+
+// apply submit tx alone and close the ledger at now, it returns the result the tx is applied with
+func apply(t *testing.T, l *Ledger, now uint32, tx data.Transaction) string {
+	if !assert.Equal(t, "tesSUCCESS", l.Submit(tx).String()) {
+		return ""
+	}
+	txs := l.Close(now)
+	assert.Equal(t, 1, len(txs))
+	return txs[0].MetaData.TransactionResult.String()
+}
+
+func token(r data.TransactionResult) string {
+	token, _ := ResultNames(r)
+	return token
+}
+
+func balance(l *Ledger, account data.Account) int64 {
+	return Drops(l.Account(account).Balance)
+}
+
+func TestSubmit(t *testing.T) {
+	l := newLedger(t)
+	payment := func(sequence uint32) *data.Payment {
+		return types.GeneratePayment(alice, bob, xrp(t, 1000), fee(t), sequence)
+	}
+	assert.Equal(t, "terNO_ACCOUNT", l.Submit(types.GeneratePayment(data.Account{9}, bob, xrp(t, 1000), fee(t), 1)).String())
+	assert.Equal(t, "terPRE_SEQ", l.Submit(payment(2)).String())
+	assert.Equal(t, "tesSUCCESS", l.Submit(payment(1)).String())
+	assert.Equal(t, "tefPAST_SEQ", l.Submit(payment(1)).String())
+	assert.Equal(t, uint32(2), l.OpenSequence(alice))
+
+	signerList, err := types.GenerateSignerListSet(alice, 2, []types.SignerEntry{types.NewSignerEntry(bob, 1), types.NewSignerEntry(carol, 1)}, fee(t), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "tefBAD_AUTH", l.SubmitSigned(signerList, bob).String())
+	assert.Equal(t, "tesSUCCESS", l.SubmitSigned(signerList, alice).String())
+	l.Close(genesis)
+	assert.Equal(t, uint32(1), *l.Account(alice).OwnerCount)
+	assert.Equal(t, int64(100000000-1000-20), balance(l, alice))
+	assert.Equal(t, int64(100000000+1000), balance(l, bob))
+
+	disable := types.GenerateAccountSet(alice, types.AsfDisableMaster, fee(t), 3)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, disable))
+	assert.Equal(t, "tefMASTER_DISABLED", l.SubmitSigned(payment(4), alice).String())
+
+	signed := func(signers ...data.Account) *data.Payment {
+		tx := payment(4)
+		for _, signer := range signers {
+			s := data.Signer{}
+			s.Signer.Account = signer
+			tx.Signers = append(tx.Signers, s)
+		}
+		return tx
+	}
+	assert.Equal(t, "tefBAD_QUORUM", token(l.SubmitMultisigned(signed(bob))))
+	assert.Equal(t, "tefBAD_SIGNATURE", token(l.SubmitMultisigned(signed(bob, data.Account{9}))))
+	assert.Equal(t, "tesSUCCESS", l.SubmitMultisigned(signed(bob, carol)).String())
+}
+
+func TestTickets(t *testing.T) {
+	l := newLedger(t)
+	create, err := types.GenerateTicketCreate(alice, 2, fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, create))
+	assert.Equal(t, 2, len(l.tickets(alice)))
+	assert.Equal(t, uint32(4), l.OpenSequence(alice))
+
+	ticketed := types.GenerateTicketPayment(alice, bob, xrp(t, 1000), fee(t), 2)
+	assert.Equal(t, "tesSUCCESS", l.Submit(ticketed).String())
+	assert.Equal(t, "tefPAST_SEQ", l.Submit(ticketed).String())
+	assert.Equal(t, "terPRE_SEQ", l.Submit(types.GenerateTicketPayment(alice, bob, xrp(t, 1000), fee(t), 4)).String())
+	both := types.GenerateTicketPayment(alice, bob, xrp(t, 1000), fee(t), 3)
+	both.Sequence = 4
+	assert.Equal(t, "temBAD_SEQUENCE", l.Submit(both).String())
+	l.Close(genesis)
+	assert.Equal(t, 1, len(l.tickets(alice)))
+	assert.Equal(t, uint32(4), l.OpenSequence(alice))
+	assert.Equal(t, "tefPAST_SEQ", l.Submit(ticketed).String())
+
+	full, err := types.GenerateTicketCreate(alice, types.MaxTickets, fee(t), 4)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecDIR_FULL", apply(t, l, genesis, full))
+	assert.Equal(t, 1, len(l.tickets(alice)))
+}
+
+func TestEscrow(t *testing.T) {
+	l := newLedger(t)
+	past, err := types.GenerateEscrowCreate(alice, bob, xrp(t, 1000), genesis, 0, nil, fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis, past))
+	create, err := types.GenerateEscrowCreate(alice, bob, xrp(t, 1000), genesis+10, genesis+20, nil, fee(t), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, create))
+	assert.Equal(t, int64(100000000-1000-20), balance(l, alice))
+	assert.Equal(t, uint32(1), *l.Account(alice).OwnerCount)
+
+	finish, err := types.GenerateEscrowFinish(bob, alice, 2, nil, nil, fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis+10, finish))
+	cancel := types.GenerateEscrowCancel(carol, alice, 2, fee(t), 1)
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis+20, cancel))
+	finish.Sequence = 2
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis+20, finish))
+	assert.Equal(t, int64(100000000+1000-20), balance(l, bob))
+	assert.Equal(t, uint32(0), *l.Account(alice).OwnerCount)
+	cancel.Sequence = 2
+	assert.Equal(t, "tecNO_TARGET", apply(t, l, genesis+30, cancel))
+
+	// an expired escrow goes back to its owner
+	create, err = types.GenerateEscrowCreate(alice, bob, xrp(t, 1000), genesis+40, genesis+50, nil, fee(t), 3)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis+30, create))
+	finish.Sequence, finish.OfferSequence = 3, 3
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis+60, finish))
+	cancel.Sequence, cancel.OfferSequence = 3, 3
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis+60, cancel))
+	assert.Equal(t, int64(100000000-1000-30), balance(l, alice))
+}
+
+func TestPayChannel(t *testing.T) {
+	l := newLedger(t)
+	create, err := types.GeneratePaymentChannelCreate(alice, bob, xrp(t, 1000), 100, data.PublicKey{}, 0, fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, create))
+	channel := types.GetPayChannelIndex(alice, bob, 1)
+	assert.NotNil(t, l.payChannel(channel))
+
+	claim := func(account data.Account, drops uint64, flags data.TransactionFlag) *data.PaymentChannelClaim {
+		tx, err := types.GeneratePaymentChannelClaim(account, channel, drops, nil, flags, fee(t), l.OpenSequence(account))
+		assert.Nil(t, err)
+		return tx
+	}
+	assert.Equal(t, "temBAD_SIGNATURE", apply(t, l, genesis, claim(bob, 100, 0)))
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis, claim(carol, 100, 0)))
+	assert.Equal(t, "tecUNFUNDED_PAYMENT", apply(t, l, genesis, claim(alice, 2000, 0)))
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, claim(alice, 400, 0)))
+	assert.Equal(t, int64(100000000+400-10), balance(l, bob))
+	assert.Equal(t, "tecUNFUNDED_PAYMENT", apply(t, l, genesis, claim(alice, 400, 0)))
+
+	fund, err := types.GeneratePaymentChannelFund(bob, channel, xrp(t, 1000), 0, fee(t), l.OpenSequence(bob))
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis, fund))
+	fund, err = types.GeneratePaymentChannelFund(alice, channel, xrp(t, 1000), 0, fee(t), l.OpenSequence(alice))
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, fund))
+	assert.Equal(t, int64(2000), Drops(l.payChannel(channel).Amount.Value))
+
+	// the source closes the channel after the settle delay, the XRP left goes back to it
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, claim(alice, 0, data.TxClose)))
+	assert.Equal(t, uint32(genesis+100), *l.payChannel(channel).Expiration)
+	before := balance(l, alice)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis+100, claim(alice, 0, 0)))
+	assert.Nil(t, l.payChannel(channel))
+	assert.Equal(t, before-10+1600, balance(l, alice))
+	assert.Equal(t, uint32(0), *l.Account(alice).OwnerCount)
+}
+
+func TestCheck(t *testing.T) {
+	l := newLedger(t)
+	create, err := types.GenerateCheckCreate(alice, bob, xrp(t, 1000), genesis+100, nil, fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, create))
+	check := types.GetCheckIndex(alice, 1)
+	assert.NotNil(t, l.object(alice, check))
+	assert.NotNil(t, l.object(bob, check))
+	assert.Equal(t, uint32(0), *l.Account(bob).OwnerCount)
+
+	cash, err := types.GenerateCheckCash(carol, check, xrp(t, 500), fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis, cash))
+	cash, err = types.GenerateCheckCash(bob, check, xrp(t, 2000), fee(t), 1)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecPATH_PARTIAL", apply(t, l, genesis, cash))
+	assert.Equal(t, "tecNO_PERMISSION", apply(t, l, genesis, types.GenerateCheckCancel(carol, check, fee(t), 2)))
+	cash, err = types.GenerateCheckCashDeliverMin(bob, check, xrp(t, 500), fee(t), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, cash))
+	assert.Equal(t, int64(100000000+1000-20), balance(l, bob))
+	assert.Nil(t, l.object(alice, check))
+	assert.Nil(t, l.object(bob, check))
+
+	// anyone cancels an expired check
+	create.Sequence = 2
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, create))
+	check = types.GetCheckIndex(alice, 2)
+	cash.CheckID, cash.Sequence = check, 3
+	assert.Equal(t, "tecEXPIRED", apply(t, l, genesis+100, cash))
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis+100, types.GenerateCheckCancel(carol, check, fee(t), 3)))
+	assert.Equal(t, uint32(0), *l.Account(alice).OwnerCount)
+}
+
+func TestOffer(t *testing.T) {
+	l := newLedger(t)
+	usd, err := data.NewAmount("10/USD/" + bob.String())
+	assert.Nil(t, err)
+	offer := func(account data.Account, flags data.TransactionFlag, takerPays, takerGets data.Amount) *data.OfferCreate {
+		tx, err := types.GenerateOfferCreate(account, takerPays, takerGets, flags, 0, 0, fee(t), l.OpenSequence(account))
+		assert.Nil(t, err)
+		return tx
+	}
+	assert.Equal(t, "tecUNFUNDED_OFFER", apply(t, l, genesis, offer(alice, 0, xrp(t, 1000), *usd)))
+	assert.Equal(t, "tecKILLED", apply(t, l, genesis, offer(alice, data.TxFillOrKill, *usd, xrp(t, 1000))))
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, offer(alice, data.TxImmediateOrCancel, *usd, xrp(t, 1000))))
+	assert.Equal(t, 0, len(l.Offers()))
+
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, offer(alice, data.TxSell, *usd, xrp(t, 1000))))
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, offer(bob, data.TxPassive, xrp(t, 1000), *usd)))
+	offers := l.Offers()
+	assert.Equal(t, 2, len(offers))
+	for _, o := range offers {
+		if o.Account.Equals(alice) {
+			assert.Equal(t, lsfSell, *o.Flags)
+		} else {
+			assert.Equal(t, lsfPassive, *o.Flags)
+		}
+	}
+	assert.Equal(t, "tesSUCCESS", apply(t, l, genesis, types.GenerateOfferCancel(alice, 4, fee(t), 5)))
+	assert.Equal(t, 1, len(l.Offers()))
+	assert.Equal(t, uint32(0), *l.Account(alice).OwnerCount)
+}
+
+func TestClose(t *testing.T) {
+	l := newLedger(t)
+	assert.Equal(t, "tesSUCCESS", l.Submit(types.GeneratePayment(alice, data.Account{9}, xrp(t, 1000), fee(t), 1)).String())
+	create, err := types.GenerateCheckCreate(alice, bob, xrp(t, 1000), 0, nil, fee(t), 2)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", l.Submit(create).String())
+	txs := l.Close(genesis)
+	assert.Equal(t, 2, len(txs))
+
+	payment := txs[0].MetaData
+	assert.Equal(t, uint32(0), payment.TransactionIndex)
+	assert.Equal(t, 2, len(payment.AffectedNodes))
+	var created, modified int
+	for _, node := range payment.AffectedNodes {
+		switch {
+		case node.CreatedNode != nil:
+			created++
+			assert.Equal(t, int64(1000), Drops(node.CreatedNode.NewFields.(*data.AccountRoot).Balance))
+		case node.ModifiedNode != nil:
+			modified++
+			assert.Equal(t, int64(100000000), Drops(node.ModifiedNode.PreviousFields.(*data.AccountRoot).Balance))
+		}
+	}
+	assert.Equal(t, 1, created)
+	assert.Equal(t, 1, modified)
+
+	check := txs[1].MetaData
+	assert.Equal(t, uint32(1), check.TransactionIndex)
+	assert.Equal(t, 2, len(check.AffectedNodes))
+	for i := 1; i < len(check.AffectedNodes); i++ {
+		a, b := nodeIndex(check.AffectedNodes[i-1]), nodeIndex(check.AffectedNodes[i])
+		assert.True(t, a.String() < b.String())
+	}
+	assert.Equal(t, 0, len(l.Close(genesis)))
+}
@@ -0,0 +1,92 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"bytes"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+func (this *Ledger) applyEscrowCreate(tx *types.EscrowCreate) data.TransactionResult {
+	root := this.accounts[tx.Account]
+	switch {
+	case tx.FinishAfter != nil && *tx.FinishAfter <= this.now, tx.CancelAfter != nil && *tx.CancelAfter <= this.now:
+		return Result("tecNO_PERMISSION")
+	case this.accounts[tx.Destination] == nil:
+		return Result("tecNO_DST")
+	}
+	amount := Drops(tx.Amount.Value)
+	if Drops(root.Balance) < amount {
+		return Result("tecUNFUNDED")
+	}
+	root.Balance, _ = data.NewNativeValue(Drops(root.Balance) - amount)
+	escrow := &data.Escrow{
+		Account:        tx.Account,
+		Destination:    tx.Destination,
+		Amount:         tx.Amount,
+		Condition:      tx.Condition,
+		CancelAfter:    tx.CancelAfter,
+		FinishAfter:    tx.FinishAfter,
+		DestinationTag: tx.DestinationTag,
+	}
+	escrow.LedgerEntryType = data.ESCROW
+	index := types.GetEscrowIndex(tx.Account, tx.Sequence)
+	escrow.LedgerIndex = &index
+	this.AddObject(tx.Account, escrow)
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applyEscrowFinish(tx *types.EscrowFinish) data.TransactionResult {
+	index := types.GetEscrowIndex(tx.Owner, tx.OfferSequence)
+	escrow, ok := this.object(tx.Owner, index).(*data.Escrow)
+	if !ok {
+		return Result("tecNO_TARGET")
+	}
+	if escrow.FinishAfter != nil && this.now <= *escrow.FinishAfter || escrow.CancelAfter != nil && this.now > *escrow.CancelAfter {
+		return Result("tecNO_PERMISSION")
+	}
+	switch {
+	case escrow.Condition == nil && tx.Fulfillment == nil:
+		// nothing to fulfill
+	case escrow.Condition == nil, tx.Condition == nil || tx.Fulfillment == nil,
+		!bytes.Equal(escrow.Condition.Bytes(), tx.Condition.Bytes()),
+		types.VerifyFulfillment(tx.Condition.Bytes(), tx.Fulfillment.Bytes()) != nil:
+		return Result("tecCRYPTOCONDITION_ERROR")
+	}
+	destination := this.accounts[escrow.Destination]
+	destination.Balance, _ = data.NewNativeValue(Drops(destination.Balance) + Drops(escrow.Amount.Value))
+	this.RemoveObject(tx.Owner, index)
+	return Result("tesSUCCESS")
+}
+
+func (this *Ledger) applyEscrowCancel(tx *data.EscrowCancel) data.TransactionResult {
+	index := types.GetEscrowIndex(tx.Owner, tx.OfferSequence)
+	escrow, ok := this.object(tx.Owner, index).(*data.Escrow)
+	if !ok {
+		return Result("tecNO_TARGET")
+	}
+	if escrow.CancelAfter == nil || this.now <= *escrow.CancelAfter {
+		return Result("tecNO_PERMISSION")
+	}
+	owner := this.accounts[tx.Owner]
+	owner.Balance, _ = data.NewNativeValue(Drops(owner.Balance) + Drops(escrow.Amount.Value))
+	this.RemoveObject(tx.Owner, index)
+	return Result("tesSUCCESS")
+}
@@ -0,0 +1,152 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"bytes"
+	"sort"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// snapshot is the state of the accounts and objects before a tx, to record the nodes it affects
+type snapshot struct {
+	roots   map[data.Account]*data.AccountRoot
+	objects map[data.Hash256]data.LedgerEntry
+}
+
+func (this *Ledger) snapshot() *snapshot {
+	before := &snapshot{
+		roots:   make(map[data.Account]*data.AccountRoot, len(this.accounts)),
+		objects: make(map[data.Hash256]data.LedgerEntry),
+	}
+	for account, root := range this.accounts {
+		before.roots[account] = cloneRoot(root)
+	}
+	for _, objects := range this.objects {
+		for _, object := range objects {
+			before.objects[*object.GetLedgerIndex()] = object
+		}
+	}
+	return before
+}
+
+// affectedNodes return the nodes changed since before, sorted by index as rippled does. Objects are
+// replaced rather than changed in place, so a modified object only has its FinalFields
+func (this *Ledger) affectedNodes(before *snapshot) data.NodeEffects {
+	var nodes data.NodeEffects
+	for account, root := range this.accounts {
+		previous, ok := before.roots[account]
+		if !ok {
+			created := &data.AccountRoot{Account: root.Account, Balance: root.Balance, Sequence: root.Sequence}
+			created.LedgerEntryType = data.ACCOUNT_ROOT
+			nodes = append(nodes, data.NodeEffect{CreatedNode: &data.AffectedNode{
+				LedgerEntryType: data.ACCOUNT_ROOT,
+				LedgerIndex:     root.LedgerIndex,
+				NewFields:       created,
+			}})
+			continue
+		}
+		if changed := changedRootFields(previous, root); changed != nil {
+			nodes = append(nodes, data.NodeEffect{ModifiedNode: &data.AffectedNode{
+				LedgerEntryType: data.ACCOUNT_ROOT,
+				LedgerIndex:     root.LedgerIndex,
+				FinalFields:     cloneRoot(root),
+				PreviousFields:  changed,
+			}})
+		}
+	}
+	after := make(map[data.Hash256]bool)
+	for _, objects := range this.objects {
+		for _, object := range objects {
+			index := *object.GetLedgerIndex()
+			if after[index] {
+				// a linked object is in more than one directory
+				continue
+			}
+			after[index] = true
+			node := &data.AffectedNode{LedgerEntryType: object.GetLedgerEntryType(), LedgerIndex: &index}
+			switch previous, ok := before.objects[index]; {
+			case !ok:
+				node.NewFields = object
+				nodes = append(nodes, data.NodeEffect{CreatedNode: node})
+			case previous != object:
+				node.FinalFields = object
+				nodes = append(nodes, data.NodeEffect{ModifiedNode: node})
+			}
+		}
+	}
+	for index, object := range before.objects {
+		if !after[index] {
+			index := index
+			nodes = append(nodes, data.NodeEffect{DeletedNode: &data.AffectedNode{
+				LedgerEntryType: object.GetLedgerEntryType(),
+				LedgerIndex:     &index,
+				FinalFields:     object,
+			}})
+		}
+	}
+	sort.Slice(nodes, func(i, j int) bool {
+		a, b := nodeIndex(nodes[i]), nodeIndex(nodes[j])
+		return bytes.Compare(a[:], b[:]) < 0
+	})
+	return nodes
+}
+
+func nodeIndex(effect data.NodeEffect) data.Hash256 {
+	switch {
+	case effect.CreatedNode != nil:
+		return *effect.CreatedNode.LedgerIndex
+	case effect.ModifiedNode != nil:
+		return *effect.ModifiedNode.LedgerIndex
+	default:
+		return *effect.DeletedNode.LedgerIndex
+	}
+}
+
+func cloneRoot(root *data.AccountRoot) *data.AccountRoot {
+	clone := *root
+	flags, sequence, ownerCount := *root.Flags, *root.Sequence, *root.OwnerCount
+	clone.Flags, clone.Sequence, clone.OwnerCount = &flags, &sequence, &ownerCount
+	clone.Balance = root.Balance.Clone()
+	return &clone
+}
+
+// changedRootFields return the previous values of the fields of the account root which changed, nil if
+// none did
+func changedRootFields(previous, root *data.AccountRoot) *data.AccountRoot {
+	changed := &data.AccountRoot{}
+	changed.LedgerEntryType = data.ACCOUNT_ROOT
+	modified := false
+	if *previous.Flags != *root.Flags {
+		changed.Flags, modified = previous.Flags, true
+	}
+	if *previous.Sequence != *root.Sequence {
+		changed.Sequence, modified = previous.Sequence, true
+	}
+	if !previous.Balance.Equals(*root.Balance) {
+		changed.Balance, modified = previous.Balance, true
+	}
+	if *previous.OwnerCount != *root.OwnerCount {
+		changed.OwnerCount, modified = previous.OwnerCount, true
+	}
+	if !modified {
+		return nil
+	}
+	return changed
+}
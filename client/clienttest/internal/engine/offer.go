@@ -0,0 +1,73 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Offer ledger entry flags
+const (
+	lsfPassive data.LedgerEntryFlag = 0x00010000
+	lsfSell    data.LedgerEntryFlag = 0x00020000
+)
+
+// applyOfferCreate place an offer, the engine does not cross offers so an immediate or cancel offer is
+// dropped and a fill or kill offer killed. It has no trust lines either, only the issuer of an issued
+// currency can offer it
+func (this *Ledger) applyOfferCreate(tx *data.OfferCreate) data.TransactionResult {
+	if tx.OfferSequence != nil {
+		this.RemoveObject(tx.Account, types.GetOfferIndex(tx.Account, *tx.OfferSequence))
+	}
+	var flags data.TransactionFlag
+	if tx.Flags != nil {
+		flags = *tx.Flags
+	}
+	switch {
+	case tx.Expiration != nil && *tx.Expiration <= this.now:
+		return Result("tecEXPIRED")
+	case tx.TakerGets.IsNative() && Drops(this.accounts[tx.Account].Balance) == 0,
+		!tx.TakerGets.IsNative() && !tx.TakerGets.Issuer.Equals(tx.Account):
+		return Result("tecUNFUNDED_OFFER")
+	case flags&data.TxFillOrKill != 0:
+		return Result("tecKILLED")
+	case flags&data.TxImmediateOrCancel != 0:
+		return Result("tesSUCCESS")
+	}
+	offer := &data.Offer{
+		Account:    &tx.Account,
+		Sequence:   &tx.Sequence,
+		TakerPays:  &tx.TakerPays,
+		TakerGets:  &tx.TakerGets,
+		Expiration: tx.Expiration,
+	}
+	var offerFlags data.LedgerEntryFlag
+	if flags&data.TxPassive != 0 {
+		offerFlags |= lsfPassive
+	}
+	if flags&data.TxSell != 0 {
+		offerFlags |= lsfSell
+	}
+	offer.Flags = &offerFlags
+	offer.LedgerEntryType = data.OFFER
+	index := types.GetOfferIndex(tx.Account, tx.Sequence)
+	offer.LedgerIndex = &index
+	this.AddObject(tx.Account, offer)
+	return Result("tesSUCCESS")
+}
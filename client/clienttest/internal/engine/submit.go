@@ -0,0 +1,131 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package engine
+
+import (
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Submit queue tx for the next close, the engine result is checked against the open ledger
+func (this *Ledger) Submit(tx data.Transaction) data.TransactionResult {
+	base := tx.GetBase()
+	if _, ok := this.accounts[base.Account]; !ok {
+		return Result("terNO_ACCOUNT")
+	}
+	sequence := this.OpenSequence(base.Account)
+	if ticket, ok := types.GetTicketSequence(tx); ok {
+		return this.submitTicket(tx, ticket, sequence)
+	}
+	switch {
+	case base.Sequence < sequence:
+		return Result("tefPAST_SEQ")
+	case base.Sequence > sequence:
+		return Result("terPRE_SEQ")
+	}
+	this.pending = append(this.pending, tx)
+	return Result("tesSUCCESS")
+}
+
+// SubmitSigned submit a single-signed tx whose signing key belongs to signer, only the master key of the
+// account is supported
+func (this *Ledger) SubmitSigned(tx data.Transaction, signer data.Account) data.TransactionResult {
+	base := tx.GetBase()
+	root := this.accounts[base.Account]
+	switch {
+	case !signer.Equals(base.Account):
+		return Result("tefBAD_AUTH")
+	case root != nil && *root.Flags&data.LsDisableMaster != 0:
+		return Result("tefMASTER_DISABLED")
+	}
+	return this.Submit(tx)
+}
+
+// SubmitMultisigned check the signers of tx against the signer list of the account, if it has one, and
+// submit tx. The signatures must be checked by the caller
+func (this *Ledger) SubmitMultisigned(tx data.Transaction) data.TransactionResult {
+	base := tx.GetBase()
+	if signerList := this.signerList(base.Account); signerList != nil {
+		var weight uint32
+		for _, s := range base.Signers {
+			w := signerWeight(signerList, s.Signer.Account)
+			if w == 0 {
+				return Result("tefBAD_SIGNATURE")
+			}
+			weight += uint32(w)
+		}
+		if weight < *signerList.SignerQuorum {
+			return Result("tefBAD_QUORUM")
+		}
+	}
+	return this.Submit(tx)
+}
+
+func signerWeight(signerList *data.SignerList, account data.Account) uint16 {
+	for _, entry := range signerList.SignerEntries {
+		if entry.Account.Equals(account) {
+			return *entry.SignerWeight
+		}
+	}
+	return 0
+}
+
+// OpenSequence return the next sequence of account in the open ledger, after the pending txs
+func (this *Ledger) OpenSequence(account data.Account) uint32 {
+	sequence := *this.accounts[account].Sequence
+	for _, pending := range this.pending {
+		if pending.GetBase().Account.Equals(account) {
+			sequence += sequenceCount(pending)
+		}
+	}
+	return sequence
+}
+
+// submitTicket queue tx consuming ticket, sequence is the next sequence of the account. The library
+// has no ticket results, so temSEQ_AND_TICKET, tefNO_TICKET and terPRE_TICKET are reported as the
+// sequence results temBAD_SEQUENCE, tefPAST_SEQ and terPRE_SEQ
+func (this *Ledger) submitTicket(tx data.Transaction, ticket, sequence uint32) data.TransactionResult {
+	base := tx.GetBase()
+	if base.Sequence != 0 {
+		return Result("temBAD_SEQUENCE")
+	}
+	for _, pending := range this.pending {
+		if other, ok := types.GetTicketSequence(pending); ok && other == ticket && pending.GetBase().Account.Equals(base.Account) {
+			return Result("tefPAST_SEQ")
+		}
+	}
+	if this.object(base.Account, types.GetTicketIndex(base.Account, ticket)) == nil {
+		if ticket >= sequence {
+			return Result("terPRE_SEQ")
+		}
+		return Result("tefPAST_SEQ")
+	}
+	this.pending = append(this.pending, tx)
+	return Result("tesSUCCESS")
+}
+
+// sequenceCount return the number of sequences tx takes, a TicketCreate takes one per ticket
+func sequenceCount(tx data.Transaction) uint32 {
+	if _, ok := types.GetTicketSequence(tx); ok {
+		return 0
+	}
+	if ticketCreate, ok := tx.(*data.TicketCreate); ok && ticketCreate.TicketCount != nil {
+		return 1 + *ticketCreate.TicketCount
+	}
+	return 1
+}
@@ -0,0 +1,183 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/shamap"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+const (
+	genesisCloseTime    = 700000000
+	closeTimeResolution = 10
+	genesisTotalCoins   = 100000000000000000
+	defaultReserveBase  = 1000000
+	defaultReserveInc   = 200000
+)
+
+// submitResponse return the response of a submit of tx_json and tx_blob with engine result r
+func submitResponse(r data.TransactionResult, txJson interface{}, txBlob string) map[string]interface{} {
+	token, message := engine.ResultNames(r)
+	return map[string]interface{}{
+		"engine_result":         token,
+		"engine_result_message": message,
+		"tx_json":               txJson,
+		"tx_blob":               txBlob,
+	}
+}
+
+// FundAccount create or reset an account holding drops
+func (this *Server) FundAccount(address string, drops int64) (*data.AccountRoot, error) {
+	account, err := data.NewAccountFromAddress(address)
+	if err != nil {
+		return nil, fmt.Errorf("FundAccount: invalid address %s, err: %s", address, err)
+	}
+	balance, err := data.NewNativeValue(drops)
+	if err != nil {
+		return nil, fmt.Errorf("FundAccount: invalid drops %d, err: %s", drops, err)
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	root := this.open.NewAccount(*account)
+	root.Balance = balance
+	return root, nil
+}
+
+// SetAccount create or replace an account root
+func (this *Server) SetAccount(root *data.AccountRoot) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.open.SetAccount(root)
+}
+
+// Account return the account root of address in the open ledger
+func (this *Server) Account(address string) *data.AccountRoot {
+	account, err := data.NewAccountFromAddress(address)
+	if err != nil {
+		return nil
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.open.Account(*account)
+}
+
+// AddObject add object to the owner directory of owner and count it in the owner reserve, the
+// ledger index of object must be set
+func (this *Server) AddObject(owner string, object data.LedgerEntry) error {
+	account, err := data.NewAccountFromAddress(owner)
+	if err != nil {
+		return fmt.Errorf("AddObject: invalid address %s, err: %s", owner, err)
+	}
+	if object.GetLedgerIndex() == nil {
+		return fmt.Errorf("AddObject: ledger index of object is not set")
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if this.open.Account(*account) == nil {
+		return fmt.Errorf("AddObject: account %s not found", owner)
+	}
+	this.open.AddObject(*account, object)
+	return nil
+}
+
+// RemoveObject remove the object of index from the owner directory of owner
+func (this *Server) RemoveObject(owner string, index data.Hash256) bool {
+	account, err := data.NewAccountFromAddress(owner)
+	if err != nil {
+		return false
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.open.RemoveObject(*account, index)
+}
+
+// Submit queue tx for the next closed ledger, the engine result is checked against the open ledger
+func (this *Server) Submit(tx data.Transaction) data.TransactionResult {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.open.Submit(tx)
+}
+
+// CloseLedger apply the queued transactions and close a new validated ledger
+func (this *Server) CloseLedger() *data.Ledger {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	parent := this.ledgers[len(this.ledgers)-1]
+	ledger, err := this.newLedger(this.open.Close(parent.CloseTime.Uint32()))
+	if err != nil {
+		this.err = fmt.Errorf("CloseLedger: %s", err)
+		return parent
+	}
+	this.ledgers = append(this.ledgers, ledger)
+	return ledger
+}
+
+// ValidatedLedger return the last closed ledger
+func (this *Server) ValidatedLedger() *data.Ledger {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.ledgers[len(this.ledgers)-1]
+}
+
+func (this *Server) ledgerByIndex(index uint32) *data.Ledger {
+	if index == 0 || int(index) > len(this.ledgers) {
+		return nil
+	}
+	return this.ledgers[index-1]
+}
+
+func (this *Server) newLedger(txs data.TransactionSlice) (*data.Ledger, error) {
+	ledger := &data.Ledger{Closed: true, Accepted: true, Transactions: txs}
+	ledger.TotalXRP = genesisTotalCoins
+	ledger.CloseResolution = closeTimeResolution
+	ledger.CloseTime.SetUint32(genesisCloseTime)
+	if len(this.ledgers) > 0 {
+		parent := this.ledgers[len(this.ledgers)-1]
+		ledger.LedgerSequence = parent.LedgerSequence + 1
+		ledger.PreviousLedger = parent.Hash
+		ledger.ParentCloseTime = parent.CloseTime
+		ledger.CloseTime.SetUint32(parent.CloseTime.Uint32() + closeTimeResolution)
+		ledger.TotalXRP = parent.TotalXRP
+	} else {
+		ledger.LedgerSequence = 1
+	}
+	items := make([]*shamap.TransactionItem, 0, len(txs))
+	for _, txm := range txs {
+		txm.LedgerSequence = ledger.LedgerSequence
+		txm.Date = ledger.CloseTime
+		txBlob, meta, err := txBlobs(txm)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, shamap.NewTransactionItem(txBlob, meta))
+		ledger.TotalXRP -= uint64(engine.Drops(&txm.GetBase().Fee))
+		this.txs[*txm.GetHash()] = txm
+	}
+	m, err := shamap.NewTransactionMap(items)
+	if err != nil {
+		return nil, err
+	}
+	// the account state tree is not maintained, account_hash is left zero
+	ledger.TransactionHash = m.Hash()
+	ledger.Hash, _ = types.LedgerHash(&ledger.LedgerHeader)
+	return ledger, nil
+}
@@ -0,0 +1,98 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+type logEntry struct {
+	level string
+	msg   string
+	args  []interface{}
+}
+
+type captureLogger struct {
+	lock    sync.Mutex
+	entries []*logEntry
+}
+
+func (this *captureLogger) log(level, msg string, args []interface{}) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.entries = append(this.entries, &logEntry{level: level, msg: msg, args: args})
+}
+
+func (this *captureLogger) Debug(msg string, args ...interface{}) { this.log("debug", msg, args) }
+func (this *captureLogger) Info(msg string, args ...interface{})  { this.log("info", msg, args) }
+func (this *captureLogger) Warn(msg string, args ...interface{})  { this.log("warn", msg, args) }
+func (this *captureLogger) Error(msg string, args ...interface{}) { this.log("error", msg, args) }
+
+func (this *captureLogger) String() string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	lines := make([]string, 0, len(this.entries))
+	for _, entry := range this.entries {
+		lines = append(lines, fmt.Sprintf("%s %s %v", entry.level, entry.msg, entry.args))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestRedact(t *testing.T) {
+	secret := "shtew2z1TRsEvpnYUGtiyvqPnYywt"
+	assert.Equal(t, `{"account":"rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF","secret":"[REDACTED]"}`,
+		client.RedactString(`{"account":"rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF","secret":"`+secret+`"}`))
+	assert.Equal(t, `{"seed": "[REDACTED]"}`, client.RedactString(`{"seed": "not a base58 seed"}`))
+	assert.Equal(t, "bad seed [REDACTED] given", client.RedactString("bad seed "+secret+" given"))
+	assert.Equal(t, "ledger 4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5",
+		client.RedactString("ledger 4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5"))
+}
+
+func TestLogger(t *testing.T) {
+	s := NewServer()
+	signer, secret := newAccount(t, "signer1")
+	logger := &captureLogger{}
+	c := s.Client().SetLogger(logger)
+	payment := &types.MultisignPayment{
+		TransactionType: "Payment",
+		Account:         goldenAccount,
+		Destination:     "rT4vRkeJsgaq7t6TVJJPsbrQp5oKMGRfN",
+		Amount:          "1000000",
+		Fee:             "30",
+		Sequence:        1,
+	}
+	_, err := c.SignFor(signer.Account.String(), secret, payment)
+	assert.Nil(t, err)
+	assert.Contains(t, logger.String(), "debug rpc request")
+	assert.Contains(t, logger.String(), "debug rpc response")
+
+	s.Close()
+	_, err = c.SignFor(signer.Account.String(), secret, payment)
+	assert.NotNil(t, err)
+	assert.NotContains(t, err.Error(), secret)
+	assert.Contains(t, err.Error(), client.Redacted)
+	assert.Contains(t, logger.String(), "error rpc request failed")
+	assert.NotContains(t, logger.String(), secret)
+}
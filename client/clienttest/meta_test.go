@@ -0,0 +1,133 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseMetaData(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	destination, _ := newAccount(t, "destination")
+	signer, _ := newAccount(t, "signer1")
+	amount, err := data.NewAmount("25000000")
+	assert.Nil(t, err)
+	payment := types.GeneratePayment(owner.Account, destination.Account, *amount, data.Value{}, 0)
+	assert.Nil(t, c.Autofill(payment, 0))
+	txBlob, err := owner.SignTx(payment)
+	assert.Nil(t, err)
+	_, err = c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	signerListSet, err := types.GenerateSignerListSet(owner.Account, 1, []types.SignerEntry{types.NewSignerEntry(signer.Account, 1)}, data.Value{}, payment.Sequence+1)
+	assert.Nil(t, err)
+	assert.Nil(t, c.Autofill(signerListSet, 0))
+	txBlob, err = owner.SignTx(signerListSet)
+	assert.Nil(t, err)
+	_, err = c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	ledger := s.CloseLedger()
+
+	tx, err := c.GetTx(payment.Hash.String(), client.LedgerValidated)
+	assert.Nil(t, err)
+	changes, err := types.ParseMetaData(&tx.TransactionWithMetaData)
+	assert.Nil(t, err)
+	assert.Equal(t, payment.Hash, changes.Hash)
+	assert.True(t, changes.Result.Success())
+	assert.Equal(t, 2, len(changes.Balances))
+	sent := changes.XrpChange(owner.Account)
+	assert.Equal(t, "-25000010", engine.DropsString(&sent))
+	received := changes.BalanceChanges(destination.Account)
+	assert.Equal(t, 1, len(received))
+	assert.True(t, received[0].IsNative())
+	assert.Equal(t, "25000000", engine.DropsString(&received[0].Change))
+	assert.Equal(t, "25000000", engine.DropsString(&received[0].Balance))
+	// the payment funded the destination
+	assert.Equal(t, 1, len(changes.Created))
+	assert.Equal(t, data.ACCOUNT_ROOT, changes.Created[0].LedgerEntryType)
+
+	binary, err := c.GetTxBinary(payment.Hash.String())
+	assert.Nil(t, err)
+	txm, err := binary.Transaction()
+	assert.Nil(t, err)
+	decoded, err := types.ParseMetaData(txm)
+	assert.Nil(t, err)
+	assert.Equal(t, len(changes.Balances), len(decoded.Balances))
+	for i := range changes.Balances {
+		assert.Equal(t, changes.Balances[i].Account, decoded.Balances[i].Account)
+		assert.True(t, changes.Balances[i].Change.Equals(decoded.Balances[i].Change))
+	}
+
+	// a blob which does not hash to the requested hash is neither returned nor cached
+	cache := client.NewLRUCache(4)
+	cached := s.Client().SetCache(cache)
+	s.Handle(client.RPC_TX, func(params json.RawMessage) (interface{}, error) {
+		return s.tx(json.RawMessage(`{"transaction": "` + payment.Hash.String() + `", "binary": true}`))
+	})
+	_, err = cached.GetTxBinary(data.Hash256{1}.String())
+	assert.NotNil(t, err)
+	assert.Equal(t, 0, cache.Len())
+	_, err = cached.GetTxBinary(payment.Hash.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, cache.Len())
+	s.Handle(client.RPC_TX, s.tx)
+
+	res, err := c.GetLedgerAt(client.LedgerAtIndex(ledger.LedgerSequence))
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(res.Ledger.Transactions))
+	for _, txm := range res.Ledger.Transactions {
+		changes, err := types.ParseMetaData(txm)
+		assert.Nil(t, err)
+		if txm.GetTransactionType() != data.SIGNER_LIST_SET {
+			continue
+		}
+		fee := changes.XrpChange(owner.Account)
+		assert.Equal(t, "-10", engine.DropsString(&fee))
+		assert.Equal(t, 1, len(changes.Created))
+		assert.Equal(t, data.SIGNER_LIST, changes.Created[0].LedgerEntryType)
+		assert.Equal(t, types.GetSignerListIndex(owner.Account), changes.Created[0].LedgerIndex)
+	}
+
+	signerListSet, err = types.GenerateSignerListSet(owner.Account, 0, nil, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, c.Autofill(signerListSet, 0))
+	txBlob, err = owner.SignTx(signerListSet)
+	assert.Nil(t, err)
+	_, err = c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	s.CloseLedger()
+	tx, err = c.GetTx(signerListSet.Hash.String())
+	assert.Nil(t, err)
+	changes, err = types.ParseMetaData(&tx.TransactionWithMetaData)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(changes.Deleted))
+	assert.Equal(t, data.SIGNER_LIST, changes.Deleted[0].LedgerEntryType)
+}
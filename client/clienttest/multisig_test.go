@@ -0,0 +1,151 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestMultisigSetup(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	config := &client.MultisigConfig{Quorum: 2, DisableMaster: true}
+	for _, passphrase := range []string{"signer1", "signer2", "signer3"} {
+		signer, _ := newAccount(t, passphrase)
+		config.Signers = append(config.Signers, types.NewSignerEntry(signer.Account, 1))
+	}
+
+	_, err := c.NewMultisigSetup(owner, config)
+	assert.NotNil(t, err)
+	_, err = s.FundAccount(address, 1200000)
+	assert.Nil(t, err)
+	_, err = c.NewMultisigSetup(owner, config)
+	assert.NotNil(t, err)
+	_, err = s.FundAccount(address, 10000000)
+	assert.Nil(t, err)
+	_, err = c.NewMultisigSetup(owner, &client.MultisigConfig{Quorum: 4, Signers: config.Signers})
+	assert.NotNil(t, err)
+
+	setup, err := c.NewMultisigSetup(owner, config)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(setup.Steps()))
+	signerListSet, ok := setup.Next().(*types.SignerListSet)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(2), signerListSet.SignerQuorum)
+	assert.Equal(t, uint32(1), signerListSet.Sequence)
+	res, err := setup.Submit()
+	assert.Nil(t, err)
+	assert.True(t, res.Accepted())
+
+	accountSet, ok := setup.Next().(*data.AccountSet)
+	assert.True(t, ok)
+	assert.Equal(t, types.AsfDisableMaster, *accountSet.SetFlag)
+	assert.Equal(t, uint32(2), accountSet.Sequence)
+	res, err = setup.Submit()
+	assert.Nil(t, err)
+	assert.Nil(t, setup.Next())
+	_, err = setup.Submit()
+	assert.NotNil(t, err)
+
+	s.CloseLedger()
+	tx, err := c.GetTx(res.Result.TxJson.Hash, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", tx.MetaData.TransactionResult.String())
+	root := s.Account(address)
+	assert.NotZero(t, *root.Flags&data.LsDisableMaster)
+	assert.Equal(t, uint32(1), *root.OwnerCount)
+	signerLists, err := c.GetAllAccountObjects(address, client.AccountObjectSignerList)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(signerLists))
+
+	// the master key is disabled
+	fee, _ := data.NewNativeValue(10)
+	txBlob, err := owner.SignTx(types.GenerateAccountSet(owner.Account, types.AsfDisableMaster, *fee, 3))
+	assert.Nil(t, err)
+	res, err = c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	assert.Equal(t, "tefMASTER_DISABLED", res.Result.EngineResult)
+}
+
+func TestAutofill(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	_, err := s.FundAccount(owner.Account.String(), 10000000)
+	assert.Nil(t, err)
+	tx := &data.AccountSet{}
+	tx.TransactionType = data.ACCOUNT_SET
+	tx.Account = owner.Account
+	assert.Nil(t, c.Autofill(tx, 0))
+	assert.Equal(t, uint32(1), tx.Sequence)
+	assert.Equal(t, "10", engine.DropsString(&tx.Fee))
+
+	tx.Fee = data.Value{}
+	assert.Nil(t, c.Autofill(tx, 2))
+	assert.Equal(t, "30", engine.DropsString(&tx.Fee))
+}
+
+func TestMultisigSetupSequence(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client().SetSequenceManager(client.NewSequenceManager())
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 10000000)
+	assert.Nil(t, err)
+	signer, _ := newAccount(t, "signer1")
+	config := &client.MultisigConfig{Quorum: 1, Signers: []types.SignerEntry{types.NewSignerEntry(signer.Account, 1)}, DisableMaster: true}
+
+	// a payment is in flight when the setup is prepared
+	amount, err := data.NewAmount("1000")
+	assert.Nil(t, err)
+	payment := types.GeneratePayment(owner.Account, signer.Account, *amount, data.Value{}, 0)
+	assert.Nil(t, c.Autofill(payment, 0))
+	assert.Equal(t, uint32(1), payment.Sequence)
+
+	setup, err := c.NewMultisigSetup(owner, config)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), setup.Steps()[0].GetBase().Sequence)
+	assert.Equal(t, uint32(3), setup.Steps()[1].GetBase().Sequence)
+
+	txBlob, err := owner.SignTx(payment)
+	assert.Nil(t, err)
+	res, err := c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	assert.True(t, res.Accepted())
+	for range setup.Steps() {
+		_, err = setup.Submit()
+		assert.Nil(t, err)
+	}
+	s.CloseLedger()
+	assert.Equal(t, uint32(4), *s.Account(address).Sequence)
+	assert.NotZero(t, *s.Account(address).Flags&data.LsDisableMaster)
+}
@@ -0,0 +1,110 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTicket(account data.Account, sequence uint32) *data.Ticket {
+	ticket := &data.Ticket{Account: &account, TicketSequence: &sequence}
+	ticket.LedgerEntryType = data.TICKET
+	index := types.GetTicketIndex(account, sequence)
+	ticket.LedgerIndex = &index
+	return ticket
+}
+
+func TestAccountObjects(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	quorum := uint32(1)
+	signerList := &data.SignerList{SignerQuorum: &quorum}
+	signerList.LedgerEntryType = data.SIGNER_LIST
+	index := types.GetSignerListIndex(owner.Account)
+	signerList.LedgerIndex = &index
+	assert.Nil(t, s.AddObject(address, signerList))
+	for sequence := uint32(1); sequence <= 5; sequence++ {
+		assert.Nil(t, s.AddObject(address, newTicket(owner.Account, sequence)))
+	}
+
+	res, err := c.GetAccountObjects(address, client.AccountObjectTicket, 2, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(res.Result.AccountObjects))
+	assert.NotEmpty(t, res.Result.Marker)
+	ticket, ok := res.Result.AccountObjects[0].(*data.Ticket)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(1), *ticket.TicketSequence)
+
+	tickets, err := c.GetAllAccountObjects(address, client.AccountObjectTicket, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(tickets))
+	all, err := c.GetAllAccountObjects(address, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 6, len(all))
+	assert.Equal(t, data.SIGNER_LIST, all[0].GetLedgerEntryType())
+
+	_, err = c.GetAccountObjects(address, "unknown", 0, "")
+	assert.NotNil(t, err)
+	assert.True(t, s.RemoveObject(address, *tickets[0].GetLedgerIndex()))
+	all, err = c.GetAllAccountObjects(address, "")
+	assert.Nil(t, err)
+	assert.Equal(t, 5, len(all))
+}
+
+func TestReserve(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 1500000)
+	assert.Nil(t, err)
+	for sequence := uint32(1); sequence <= 2; sequence++ {
+		assert.Nil(t, s.AddObject(address, newTicket(owner.Account, sequence)))
+	}
+
+	reserve, err := c.GetReserve(address)
+	assert.Nil(t, err)
+	assert.Equal(t, client.Reserve{Base: 1000000, Increment: 200000, OwnerCount: 2, Balance: 1500000}, *reserve)
+	assert.Equal(t, uint64(1400000), reserve.Required())
+	assert.Equal(t, uint64(100000), reserve.Spendable())
+	assert.False(t, reserve.CanAfford(1))
+
+	s.SetReserve(10000000, 2000000)
+	s.CloseLedger()
+	reserve, err = c.GetReserve(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(14000000), reserve.Required())
+	assert.Equal(t, uint64(0), reserve.Spendable())
+
+	unfunded, _ := newAccount(t, "unfunded")
+	_, err = c.GetReserve(unfunded.Account.String())
+	assert.NotNil(t, err)
+}
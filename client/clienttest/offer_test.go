@@ -0,0 +1,119 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffers(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	issuer, _ := newAccount(t, "issuer")
+	address := issuer.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	trader, _ := newAccount(t, "trader")
+	_, err = s.FundAccount(trader.Account.String(), 100000000)
+	assert.Nil(t, err)
+	usd := "/USD/" + address
+
+	// the issuer sells USD for XRP at 3, 2 and 2.5 XRP per USD
+	var sequences []uint32
+	for _, price := range []string{"30000000", "20000000", "25000000"} {
+		create, err := types.GenerateOfferCreate(issuer.Account, newAmount(t, price), newAmount(t, "10"+usd), 0, 0, 0, data.Value{}, 0)
+		assert.Nil(t, err)
+		assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, issuer, create))
+		sequences = append(sequences, create.Sequence)
+	}
+	// the trader has no USD to sell, and nothing crosses in the mock
+	create, err := types.GenerateOfferCreate(trader.Account, newAmount(t, "10"+usd), newAmount(t, "20000000"), data.TxFillOrKill, 0, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecKILLED", submitValidated(t, s, c, trader, create))
+	create, err = types.GenerateOfferCreate(trader.Account, newAmount(t, "10"+usd), newAmount(t, "20000000"), data.TxImmediateOrCancel, 0, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, trader, create))
+	create, err = types.GenerateOfferCreate(trader.Account, newAmount(t, "20000000"), newAmount(t, "10"+usd), 0, 0, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tecUNFUNDED_OFFER", submitValidated(t, s, c, trader, create))
+	create, err = types.GenerateOfferCreate(trader.Account, newAmount(t, "10"+usd), newAmount(t, "15000000"), data.TxPassive, 0, 0, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, trader, create))
+
+	offers, err := c.GetAllAccountOffers(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(offers))
+	page, err := c.GetAccountOffers(address, 2, "", client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(page.Result.Offers))
+	assert.NotEqual(t, "", page.Result.Marker)
+	traderOffers, err := c.GetAllAccountOffers(trader.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(traderOffers))
+	assert.Equal(t, create.Sequence, traderOffers[0].Seq)
+	assert.Equal(t, uint32(0x00010000), traderOffers[0].Flags)
+
+	// the book of USD for XRP is sorted by quality, the cheapest USD first
+	gets, pays := newAmount(t, "1"+usd).Asset(), newAmount(t, "1").Asset()
+	book, err := c.GetBookOffers(*gets, *pays, "", 2, "", client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(book.Result.Offers))
+	assert.NotEqual(t, "", book.Result.Marker)
+	best := book.Result.Offers[0]
+	assert.Equal(t, sequences[1], *best.Sequence)
+	assert.Equal(t, types.GetOfferIndex(issuer.Account, sequences[1]), *best.LedgerIndex)
+	assert.Equal(t, big.NewRat(2000000, 1), best.Quality.Rat())
+	assert.Equal(t, big.NewRat(2, 1), best.Quality.Price(true, false))
+	assert.Equal(t, sequences[2], *book.Result.Offers[1].Sequence)
+	assert.Equal(t, -1, best.Quality.Cmp(&book.Result.Offers[1].Quality))
+	book, err = c.GetBookOffers(*gets, *pays, "", 2, book.Result.Marker, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(book.Result.Offers))
+	assert.Equal(t, "", book.Result.Marker)
+	assert.Equal(t, sequences[0], *book.Result.Offers[0].Sequence)
+	// the other side of the book holds the offer of the trader, funded by XRP
+	book, err = c.GetBookOffers(*pays, *gets, "", 0, "", client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 1, len(book.Result.Offers))
+	assert.Equal(t, engine.DropsString(s.Account(trader.Account.String()).Balance), book.Result.Offers[0].OwnerFunds)
+
+	// replace the 3 XRP offer and cancel the 2.5 XRP one
+	create, err = types.GenerateOfferCreate(issuer.Account, newAmount(t, "22000000"), newAmount(t, "10"+usd), data.TxSell, 0, sequences[0], data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, issuer, create))
+	cancel := types.GenerateOfferCancel(issuer.Account, sequences[2], data.Value{}, 0)
+	assert.Equal(t, "tesSUCCESS", submitValidated(t, s, c, issuer, cancel))
+	offers, err = c.GetAllAccountOffers(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(offers))
+	book, err = c.GetBookOffers(*gets, *pays, "", 0, "", client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, 2, len(book.Result.Offers))
+	assert.Equal(t, sequences[1], *book.Result.Offers[0].Sequence)
+	assert.Equal(t, create.Sequence, *book.Result.Offers[1].Sequence)
+	assert.Equal(t, "2200000", book.Result.Offers[1].Quality.String())
+}
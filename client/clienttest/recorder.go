@@ -0,0 +1,241 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"sync"
+
+	"github.com/polynetwork/ripple-sdk/client"
+)
+
+// Exchange is one request/response pair of a golden file
+type Exchange struct {
+	Method     string          `json:"method"`
+	Request    json.RawMessage `json:"request"`
+	StatusCode int             `json:"status_code"`
+	Response   json.RawMessage `json:"response,omitempty"`
+	Body       string          `json:"body,omitempty"`
+}
+
+// Recorder is a http.RoundTripper recording every exchange with the node, use it by
+// RpcClient.SetHttpClient(recorder.HttpClient()) and write the golden file by Save
+type Recorder struct {
+	transport http.RoundTripper
+	lock      sync.Mutex
+	exchanges []*Exchange
+}
+
+// NewRecorder return a recorder sending the requests through transport, http.DefaultTransport if nil
+func NewRecorder(transport http.RoundTripper) *Recorder {
+	if transport == nil {
+		transport = http.DefaultTransport
+	}
+	return &Recorder{transport: transport}
+}
+
+func (this *Recorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("Recorder: read request body err: %s", err)
+	}
+	resp, err := this.transport.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close()
+	if err != nil {
+		return nil, fmt.Errorf("Recorder: read response body err: %s", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+	exchange, err := newExchange(reqBody, resp.StatusCode, respBody)
+	if err != nil {
+		return nil, fmt.Errorf("Recorder: %s", err)
+	}
+	this.lock.Lock()
+	this.exchanges = append(this.exchanges, exchange)
+	this.lock.Unlock()
+	return resp, nil
+}
+
+// HttpClient return a http client using the recorder as transport
+func (this *Recorder) HttpClient() *http.Client {
+	return &http.Client{Transport: this}
+}
+
+// Exchanges return the exchanges recorded so far
+func (this *Recorder) Exchanges() []*Exchange {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]*Exchange{}, this.exchanges...)
+}
+
+// Save write the recorded exchanges to the golden file path
+func (this *Recorder) Save(path string) error {
+	raw, err := json.MarshalIndent(this.Exchanges(), "", "  ")
+	if err != nil {
+		return fmt.Errorf("Save: marshal exchanges err: %s", err)
+	}
+	if err := ioutil.WriteFile(path, append(raw, '\n'), 0644); err != nil {
+		return fmt.Errorf("Save: write %s err: %s", path, err)
+	}
+	return nil
+}
+
+// Replayer is a http.RoundTripper answering requests from a golden file without any network.
+// A request matches an exchange when the json bodies are equal, identical requests are answered
+// in the recorded order. Unmatched requests fail the round trip and are kept for Unmatched
+type Replayer struct {
+	lock      sync.Mutex
+	exchanges []*Exchange
+	used      []bool
+	unmatched []string
+}
+
+// NewReplayer load the golden file path written by Recorder.Save
+func NewReplayer(path string) (*Replayer, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("NewReplayer: read %s err: %s", path, err)
+	}
+	var exchanges []*Exchange
+	if err := json.Unmarshal(raw, &exchanges); err != nil {
+		return nil, fmt.Errorf("NewReplayer: unmarshal %s err: %s", path, err)
+	}
+	for _, exchange := range exchanges {
+		if exchange.Request, err = canonical(exchange.Request); err != nil {
+			return nil, fmt.Errorf("NewReplayer: invalid request %s in %s, err: %s", exchange.Method, path, err)
+		}
+	}
+	return &Replayer{exchanges: exchanges, used: make([]bool, len(exchanges))}, nil
+}
+
+func (this *Replayer) RoundTrip(req *http.Request) (*http.Response, error) {
+	reqBody, err := readBody(req)
+	if err != nil {
+		return nil, fmt.Errorf("Replayer: read request body err: %s", err)
+	}
+	request, err := canonical(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("Replayer: request is not json: %s", client.Redact(reqBody))
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	for i, exchange := range this.exchanges {
+		if this.used[i] || !bytes.Equal(exchange.Request, request) {
+			continue
+		}
+		this.used[i] = true
+		body := []byte(exchange.Body)
+		if len(exchange.Response) > 0 {
+			body = exchange.Response
+		}
+		return &http.Response{
+			Status:        http.StatusText(exchange.StatusCode),
+			StatusCode:    exchange.StatusCode,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          ioutil.NopCloser(bytes.NewReader(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	this.unmatched = append(this.unmatched, string(request))
+	return nil, fmt.Errorf("Replayer: no recorded response for request %s", request)
+}
+
+// HttpClient return a http client using the replayer as transport
+func (this *Replayer) HttpClient() *http.Client {
+	return &http.Client{Transport: this}
+}
+
+// Unmatched return the requests which were not found in the golden file
+func (this *Replayer) Unmatched() []string {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]string{}, this.unmatched...)
+}
+
+// Check fail t for every unmatched request, call it at the end of the test so that
+// an unmatched request is reported even if the caller swallowed the error
+func (this *Replayer) Check(t TestingT) {
+	t.Helper()
+	for _, request := range this.Unmatched() {
+		t.Errorf("Replayer: unmatched request %s", request)
+	}
+}
+
+// TestingT is the subset of testing.TB used by Replayer.Check
+type TestingT interface {
+	Helper()
+	Errorf(format string, args ...interface{})
+}
+
+func newExchange(reqBody []byte, statusCode int, respBody []byte) (*Exchange, error) {
+	request, err := canonical(reqBody)
+	if err != nil {
+		return nil, fmt.Errorf("request is not json: %s", client.Redact(reqBody))
+	}
+	req := &struct {
+		Method string `json:"method"`
+	}{}
+	json.Unmarshal(request, req)
+	exchange := &Exchange{Method: req.Method, Request: request, StatusCode: statusCode}
+	// rippled echoes the request in the result of an error, so the response is redacted as well. A non
+	// json body, e.g. a proxy error page, is kept as text
+	if exchange.Response, err = canonical(respBody); err != nil {
+		exchange.Body = client.Redact(respBody)
+	}
+	return exchange, nil
+}
+
+func readBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close()
+	if err != nil {
+		return nil, err
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	return body, nil
+}
+
+// canonical return the compact json of the request or response raw with the object keys sorted and
+// the secrets redacted, so that golden files never hold a secret
+func canonical(raw []byte) (json.RawMessage, error) {
+	var v interface{}
+	decoder := json.NewDecoder(bytes.NewReader(raw))
+	decoder.UseNumber()
+	if err := decoder.Decode(&v); err != nil {
+		return nil, err
+	}
+	compact, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return json.RawMessage(client.Redact(compact)), nil
+}
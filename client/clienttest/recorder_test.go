@@ -0,0 +1,142 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+var update = flag.Bool("update", false, "record the golden files against the mock server")
+
+const goldenAccount = "rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF"
+
+type fakeT struct {
+	errors []string
+}
+
+func (this *fakeT) Helper() {}
+
+func (this *fakeT) Errorf(format string, args ...interface{}) {
+	this.errors = append(this.errors, fmt.Sprintf(format, args...))
+}
+
+func TestRecordAndReplay(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	_, err := s.FundAccount(goldenAccount, 100000000)
+	assert.Nil(t, err)
+
+	recorder := NewRecorder(nil)
+	c := client.NewRpcClient().SetAddress(s.URL).SetHttpClient(recorder.HttpClient())
+	recorded, err := c.GetAccountInfo(goldenAccount)
+	assert.Nil(t, err)
+	_, err = c.GetCurrentHeight()
+	assert.Nil(t, err)
+	_, err = c.GetCurrentHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(recorder.Exchanges()))
+	assert.Equal(t, client.RPC_ACCOUNT_INFO, recorder.Exchanges()[0].Method)
+
+	path := filepath.Join(t.TempDir(), "golden.json")
+	assert.Nil(t, recorder.Save(path))
+	replayer, err := NewReplayer(path)
+	assert.Nil(t, err)
+	s.Close()
+
+	// the address is never dialed in replay mode
+	c = client.NewRpcClient().SetAddress("http://127.0.0.1:1").SetHttpClient(replayer.HttpClient())
+	replayed, err := c.GetAccountInfo(goldenAccount)
+	assert.Nil(t, err)
+	assert.Equal(t, recorded, replayed)
+	for i := 0; i < 2; i++ {
+		height, err := c.GetCurrentHeight()
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(1), height)
+	}
+	replayer.Check(t)
+
+	_, err = c.GetCurrentHeight()
+	assert.NotNil(t, err)
+	_, err = c.GetAccountInfo("rT4vRkeJsgaq7t6TVJJPsbrQp5oKMGRfN")
+	assert.NotNil(t, err)
+	ft := &fakeT{}
+	replayer.Check(ft)
+	assert.Equal(t, 2, len(ft.errors))
+}
+
+func TestRecordRedact(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	recorder := NewRecorder(nil)
+	c := client.NewRpcClient().SetAddress(s.URL).SetHttpClient(recorder.HttpClient())
+
+	// rippled echoes the request of a failed call in result.request
+	secret := "snoPBrXtMeMyMHUVTgbuqAfg1SUTb"
+	params := map[string]interface{}{
+		"account":    goldenAccount,
+		"secret":     secret,
+		"seed":       secret,
+		"passphrase": "masterpassphrase",
+		"tx_json":    map[string]interface{}{"TransactionType": "Payment"},
+	}
+	err := c.Call(context.Background(), client.RPC_SIGN_FOR, params, nil)
+	assert.NotNil(t, err)
+	assert.Equal(t, 1, len(recorder.Exchanges()))
+	assert.Contains(t, string(recorder.Exchanges()[0].Response), `"request"`)
+
+	path := filepath.Join(t.TempDir(), "golden.json")
+	assert.Nil(t, recorder.Save(path))
+	golden, err := ioutil.ReadFile(path)
+	assert.Nil(t, err)
+	assert.NotContains(t, string(golden), secret)
+	assert.NotContains(t, string(golden), "masterpassphrase")
+	assert.Contains(t, string(golden), client.Redacted)
+}
+
+func TestReplayGolden(t *testing.T) {
+	path := filepath.Join("testdata", "account_info.json")
+	if *update {
+		s := NewServer()
+		defer s.Close()
+		_, err := s.FundAccount(goldenAccount, 100000000)
+		assert.Nil(t, err)
+		recorder := NewRecorder(nil)
+		c := client.NewRpcClient().SetAddress(s.URL).SetHttpClient(recorder.HttpClient())
+		_, err = c.GetAccountInfo(goldenAccount)
+		assert.Nil(t, err)
+		assert.Nil(t, recorder.Save(path))
+	}
+
+	replayer, err := NewReplayer(path)
+	assert.Nil(t, err)
+	defer replayer.Check(t)
+	c := client.NewRpcClient().SetAddress("http://127.0.0.1:1").SetHttpClient(replayer.HttpClient())
+	info, err := c.GetAccountInfo(goldenAccount)
+	assert.Nil(t, err)
+	assert.Equal(t, goldenAccount, info.AccountData.Account.String())
+	assert.Equal(t, "100000000", engine.DropsString(info.AccountData.Balance))
+}
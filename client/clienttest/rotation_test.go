@@ -0,0 +1,125 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestDiffSignerList(t *testing.T) {
+	signer1, _ := newAccount(t, "signer1")
+	signer2, _ := newAccount(t, "signer2")
+	signer3, _ := newAccount(t, "signer3")
+	current := &types.SignerList{SignerQuorum: 2, SignerEntries: []types.SignerEntry{
+		types.NewSignerEntry(signer1.Account, 1), types.NewSignerEntry(signer2.Account, 1)}}
+
+	diff := client.DiffSignerList(current, 2, current.SignerEntries)
+	assert.True(t, diff.Empty())
+	diff = client.DiffSignerList(current, 3, []types.SignerEntry{
+		types.NewSignerEntry(signer2.Account, 2), types.NewSignerEntry(signer3.Account, 1)})
+	assert.False(t, diff.Empty())
+	assert.Equal(t, []types.SignerEntry{types.NewSignerEntry(signer3.Account, 1)}, diff.Added)
+	assert.Equal(t, signer1.Account, diff.Removed[0])
+	assert.Equal(t, []types.SignerEntry{types.NewSignerEntry(signer2.Account, 2)}, diff.Reweighted)
+	assert.Equal(t, uint32(2), diff.OldQuorum)
+	assert.Equal(t, uint32(3), diff.NewQuorum)
+	diff = client.DiffSignerList(nil, 1, current.SignerEntries)
+	assert.Equal(t, 2, len(diff.Added))
+}
+
+func TestSignerRotation(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 10000000)
+	assert.Nil(t, err)
+	signers := make([]*types.Account, 0, 4)
+	for _, passphrase := range []string{"signer1", "signer2", "signer3", "signer4"} {
+		signer, _ := newAccount(t, passphrase)
+		signers = append(signers, signer)
+	}
+	_, err = c.NewSignerRotation(address, 1, []types.SignerEntry{types.NewSignerEntry(signers[0].Account, 1)})
+	assert.NotNil(t, err)
+
+	config := &client.MultisigConfig{Quorum: 2, DisableMaster: true}
+	for _, signer := range signers[:3] {
+		config.Signers = append(config.Signers, types.NewSignerEntry(signer.Account, 1))
+	}
+	setup, err := c.NewMultisigSetup(owner, config)
+	assert.Nil(t, err)
+	for setup.Next() != nil {
+		_, err = setup.Submit()
+		assert.Nil(t, err)
+	}
+	s.CloseLedger()
+	current, err := c.GetSignerList(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), current.SignerQuorum)
+	assert.Equal(t, 3, len(current.SignerEntries))
+	_, err = c.NewSignerRotation(address, 2, config.Signers)
+	assert.NotNil(t, err)
+
+	desired := []types.SignerEntry{
+		types.NewSignerEntry(signers[1].Account, 1),
+		types.NewSignerEntry(signers[2].Account, 1),
+		types.NewSignerEntry(signers[3].Account, 2),
+	}
+	rotation, err := c.NewSignerRotation(address, 3, desired)
+	assert.Nil(t, err)
+	assert.Equal(t, []types.SignerEntry{desired[2]}, rotation.Diff.Added)
+	assert.Equal(t, 1, len(rotation.Diff.Removed))
+	assert.Equal(t, uint32(3), rotation.Tx.Sequence)
+	assert.Equal(t, "40", engine.DropsString(&rotation.Tx.Fee))
+
+	// the new signer can not sign the rotation
+	signed, err := signers[3].MultiSignTransaction(rotation.RawTx())
+	assert.Nil(t, err)
+	assert.NotNil(t, rotation.AddSigned(signed))
+	signed, err = signers[0].MultiSignTransaction(rotation.RawTx())
+	assert.Nil(t, err)
+	assert.Nil(t, rotation.AddSigned(signed))
+	assert.False(t, rotation.QuorumReached())
+	_, err = rotation.Submit()
+	assert.NotNil(t, err)
+	signed, err = signers[1].MultiSignTransaction(rotation.RawTx())
+	assert.Nil(t, err)
+	assert.Nil(t, rotation.AddSigned(signed))
+	assert.True(t, rotation.QuorumReached())
+	res, err := rotation.Submit()
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", res.Result.EngineResult)
+
+	s.CloseLedger()
+	tx, err := c.GetTx(res.Result.TxJson.Hash, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.True(t, tx.MetaData.TransactionResult.Success())
+	current, err = c.GetSignerList(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), current.SignerQuorum)
+	assert.Equal(t, uint16(2), current.Weight(signers[3].Account))
+	assert.Equal(t, uint16(0), current.Weight(signers[0].Account))
+	assert.Equal(t, uint32(1), *s.Account(address).OwnerCount)
+}
@@ -0,0 +1,177 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"sort"
+	"sync"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSequenceManager(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	manager := client.NewSequenceManager()
+	c := s.Client().SetSequenceManager(manager)
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	destination, _ := newAccount(t, "destination")
+	amount, err := data.NewAmount("1000")
+	assert.Nil(t, err)
+	newPayment := func() *data.Payment {
+		payment := types.GeneratePayment(owner.Account, destination.Account, *amount, data.Value{}, 0)
+		assert.Nil(t, c.Autofill(payment, 0))
+		return payment
+	}
+	submit := func(payment *data.Payment) string {
+		txBlob, err := owner.SignTx(payment)
+		assert.Nil(t, err)
+		res, err := c.SubmitTx(txBlob)
+		assert.Nil(t, err)
+		return res.Result.EngineResult
+	}
+
+	// a burst of payments gets consecutive sequences
+	sequence := *s.Account(address).Sequence
+	payments := make([]*data.Payment, 10)
+	wg := &sync.WaitGroup{}
+	for i := range payments {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payments[i] = newPayment()
+		}(i)
+	}
+	wg.Wait()
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Sequence < payments[j].Sequence })
+	for i, payment := range payments {
+		assert.Equal(t, sequence+uint32(i), payment.Sequence)
+		assert.Equal(t, "tesSUCCESS", submit(payment))
+	}
+	s.CloseLedger()
+	assert.Equal(t, sequence+10, *s.Account(address).Sequence)
+
+	// another sender used the next sequence
+	other := types.GeneratePayment(owner.Account, destination.Account, *amount, data.Value{}, sequence+10)
+	other.Fee = payments[0].Fee
+	assert.Equal(t, "tesSUCCESS", submit(other))
+	payment := newPayment()
+	assert.Equal(t, sequence+10, payment.Sequence)
+	assert.Equal(t, "tefPAST_SEQ", submit(payment))
+	payment = newPayment()
+	assert.Equal(t, sequence+11, payment.Sequence)
+	assert.Equal(t, "tesSUCCESS", submit(payment))
+
+	// an allocated sequence is abandoned, terPRE_SEQ does not resync since an earlier tx may be in flight
+	newPayment()
+	payment = newPayment()
+	assert.Equal(t, "terPRE_SEQ", submit(payment))
+	assert.Equal(t, sequence+14, newPayment().Sequence)
+	manager.Reset(owner.Account)
+	payment = newPayment()
+	assert.Equal(t, sequence+12, payment.Sequence)
+	assert.Equal(t, "tesSUCCESS", submit(payment))
+	s.CloseLedger()
+	assert.Equal(t, sequence+13, *s.Account(address).Sequence)
+
+	// the last allocated sequence is given back when its tx is not applied
+	intruder, _ := newAccount(t, "intruder")
+	payment = newPayment()
+	assert.Equal(t, sequence+13, payment.Sequence)
+	txBlob, err := intruder.SignTx(payment)
+	assert.Nil(t, err)
+	res, err := c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	assert.Equal(t, "tefBAD_AUTH", res.Result.EngineResult)
+	assert.Equal(t, sequence+13, newPayment().Sequence)
+	manager.Reset(owner.Account)
+}
+
+func TestSequenceManagerInFlight(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client().SetSequenceManager(client.NewSequenceManager())
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	intruder, _ := newAccount(t, "intruder")
+	destination, _ := newAccount(t, "destination")
+	amount, err := data.NewAmount("1000")
+	assert.Nil(t, err)
+	sequence := *s.Account(address).Sequence
+
+	// five payments are in flight at once, the third one fails without consuming its sequence
+	payments := make([]*data.Payment, 5)
+	results := make(map[uint32]string)
+	lock := &sync.Mutex{}
+	wg := &sync.WaitGroup{}
+	for i := range payments {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			payment := types.GeneratePayment(owner.Account, destination.Account, *amount, data.Value{}, 0)
+			assert.Nil(t, c.Autofill(payment, 0))
+			signer := owner
+			if payment.Sequence == sequence+2 {
+				signer = intruder
+			}
+			txBlob, err := signer.SignTx(payment)
+			assert.Nil(t, err)
+			res, err := c.SubmitTx(txBlob)
+			assert.Nil(t, err)
+			lock.Lock()
+			payments[i], results[payment.Sequence] = payment, res.Result.EngineResult
+			lock.Unlock()
+		}(i)
+	}
+	wg.Wait()
+	sort.Slice(payments, func(i, j int) bool { return payments[i].Sequence < payments[j].Sequence })
+	for i, payment := range payments {
+		assert.Equal(t, sequence+uint32(i), payment.Sequence)
+	}
+
+	// the sequences held by the other payments are not handed out again
+	next := types.GeneratePayment(owner.Account, destination.Account, *amount, data.Value{}, 0)
+	assert.Nil(t, c.Autofill(next, 0))
+	assert.Equal(t, sequence+5, next.Sequence)
+
+	// the failed payment is signed again and the ones behind it resubmitted
+	assert.Equal(t, "tefBAD_AUTH", results[sequence+2])
+	for _, payment := range append(payments, next) {
+		if results[payment.Sequence] == "tesSUCCESS" {
+			continue
+		}
+		txBlob, err := owner.SignTx(payment)
+		assert.Nil(t, err)
+		res, err := c.SubmitTx(txBlob)
+		assert.Nil(t, err)
+		assert.Equal(t, "tesSUCCESS", res.Result.EngineResult)
+	}
+	s.CloseLedger()
+	assert.Equal(t, sequence+6, *s.Account(address).Sequence)
+}
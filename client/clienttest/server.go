@@ -0,0 +1,260 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package clienttest provides an in-process rippled JSON-RPC server backed by a scriptable
+// in-memory ledger, for unit testing code built on RpcClient without a live node. The server only
+// serves the rpc methods, the txs it accepts are applied by the internal engine package, which covers
+// the subset of the ledger rules the client tests rely on.
+package clienttest
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/rubblelabs/ripple/websockets"
+)
+
+// HandlerFunc serve one rpc method, it returns the result object or an *RpcError
+type HandlerFunc func(params json.RawMessage) (interface{}, error)
+
+// RpcError is the error returned by rippled in the result object
+type RpcError = client.RpcError
+
+var (
+	ErrUnknownCmd     = &RpcError{Name: "unknownCmd", Code: 32, Message: "Unknown method."}
+	ErrInvalidParams  = &RpcError{Name: "invalidParams", Code: 31, Message: "Invalid parameters."}
+	ErrLedgerNotFound = &RpcError{Name: "lgrNotFound", Code: 21, Message: "ledgerNotFound"}
+	ErrTxNotFound     = &RpcError{Name: "txnNotFound", Code: 29, Message: "Transaction not found."}
+	ErrActNotFound    = &RpcError{Name: "actNotFound", Code: 19, Message: "Account not found."}
+	ErrBadSecret      = &RpcError{Name: "badSecret", Code: 41, Message: "Secret does not match account."}
+)
+
+// Server is a mock rippled node, every ledger it closes is validated immediately
+type Server struct {
+	*httptest.Server
+	lock     sync.Mutex
+	handlers map[string]HandlerFunc
+	ledgers  []*data.Ledger
+	txs      map[data.Hash256]*data.TransactionWithMetaData
+	open     *engine.Ledger
+	fee      *websockets.FeeResult
+	requests []*client.JsonRpcRequest
+	batch    bool
+	state    string
+	reserve  [2]uint64
+	err      error
+}
+
+// NewServer start a server whose genesis ledger 1 is closed and validated
+func NewServer() *Server {
+	s := &Server{
+		handlers: make(map[string]HandlerFunc),
+		txs:      make(map[data.Hash256]*data.TransactionWithMetaData),
+		open:     engine.NewLedger(),
+		fee:      defaultFee(),
+		batch:    true,
+		state:    "full",
+		reserve:  [2]uint64{defaultReserveBase, defaultReserveInc},
+	}
+	// the genesis ledger has no tx to encode, it can not fail
+	genesis, _ := s.newLedger(nil)
+	s.ledgers = append(s.ledgers, genesis)
+	s.Handle(client.RPC_LEDGER_CLOSED, s.ledgerClosed)
+	s.Handle(client.RPC_LEDGER, s.ledger)
+	s.Handle(client.RPC_TX, s.tx)
+	s.Handle(client.RPC_ACCOUNT_INFO, s.accountInfo)
+	s.Handle(client.RPC_FEE, s.getFee)
+	s.Handle(client.RPC_SIGN_FOR, s.signFor)
+	s.Handle(client.RPC_SUBMIT_MULTISIGNED, s.submitMultisigned)
+	s.Handle(client.RPC_SUBMIT, s.submitTx)
+	s.Handle(client.RPC_SERVER_INFO, s.serverInfo)
+	s.Handle(client.RPC_SERVER_STATE, s.serverState)
+	s.Handle(client.RPC_ACCOUNT_OBJECTS, s.accountObjects)
+	s.Handle(client.RPC_ACCOUNT_CHANNELS, s.accountChannels)
+	s.Handle(client.RPC_ACCOUNT_OFFERS, s.accountOffers)
+	s.Handle(client.RPC_BOOK_OFFERS, s.bookOffers)
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Err return the internal error which broke the server, e.g. a tx of a fixture it could not encode
+// when closing a ledger. Every request fails with it from then on, so that only the test using the
+// server fails
+func (this *Server) Err() error {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.err
+}
+
+// Client return a RpcClient connected to the server
+func (this *Server) Client() *client.RpcClient {
+	return client.NewRpcClient().SetAddress(this.URL)
+}
+
+// Handle set or override the handler of method
+func (this *Server) Handle(method string, handler HandlerFunc) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.handlers[method] = handler
+}
+
+// SetBatch enable or disable the batch method, it is enabled by default as in rippled
+func (this *Server) SetBatch(enabled bool) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.batch = enabled
+}
+
+// SetServerState set the server_state reported, "full" by default
+func (this *Server) SetServerState(state string) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.state = state
+}
+
+// SetReserve set the base and owner reserves in drops
+func (this *Server) SetReserve(base, inc uint64) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.reserve = [2]uint64{base, inc}
+}
+
+// Requests return all the requests received, the items of a batch are recorded one by one
+func (this *Server) Requests() []*client.JsonRpcRequest {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return append([]*client.JsonRpcRequest{}, this.requests...)
+}
+
+type rpcRequest struct {
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+func (this *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	body, err := ioutil.ReadAll(r.Body)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	req := &rpcRequest{}
+	if err := json.Unmarshal(body, req); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	this.lock.Lock()
+	batch := this.batch
+	this.lock.Unlock()
+
+	var resp interface{}
+	if req.Method == client.RPC_BATCH && batch {
+		results := make([]interface{}, 0, len(req.Params))
+		for _, raw := range req.Params {
+			item := &rpcRequest{}
+			if err := json.Unmarshal(raw, item); err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			results = append(results, map[string]interface{}{"result": this.handle(item)})
+		}
+		resp = results
+	} else {
+		resp = map[string]interface{}{"result": this.handle(req)}
+	}
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(resp)
+}
+
+func (this *Server) handle(req *rpcRequest) map[string]interface{} {
+	params := json.RawMessage("{}")
+	if len(req.Params) > 0 {
+		params = req.Params[0]
+	}
+
+	this.lock.Lock()
+	this.requests = append(this.requests, &client.JsonRpcRequest{Method: req.Method, Params: []interface{}{params}})
+	handler, ok := this.handlers[req.Method]
+	failure := this.err
+	this.lock.Unlock()
+	if !ok {
+		handler = func(json.RawMessage) (interface{}, error) { return nil, ErrUnknownCmd }
+	}
+	if failure != nil {
+		handler = func(json.RawMessage) (interface{}, error) { return nil, failure }
+	}
+
+	result, err := handler(params)
+	var resp map[string]interface{}
+	if err == nil {
+		resp, err = toMap(result)
+	}
+	if err != nil {
+		rpcErr, ok := err.(*RpcError)
+		if !ok {
+			rpcErr = &RpcError{Name: "internal", Code: 73, Message: err.Error()}
+		}
+		resp, _ = toMap(rpcErr)
+		resp["status"] = "error"
+		resp["request"] = json.RawMessage(params)
+	} else if _, ok := resp["status"]; !ok {
+		resp["status"] = "success"
+	}
+	return resp
+}
+
+func toMap(v interface{}) (map[string]interface{}, error) {
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	m := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
+func defaultFee() *websockets.FeeResult {
+	fee := &websockets.FeeResult{
+		CurrentLedgerSize:  0,
+		ExpectedLedgerSize: 1000,
+		MaxQueueSize:       20000,
+		Status:             "success",
+	}
+	drops := func(s string) data.Value {
+		v, _ := data.NewNativeValue(0)
+		if parsed, err := data.NewValue(s, true); err == nil {
+			v = parsed
+		}
+		return *v
+	}
+	fee.Drops.BaseFee = drops("10")
+	fee.Drops.MedianFee = drops("5000")
+	fee.Drops.MinimumFee = drops("10")
+	fee.Drops.OpenLedgerFee = drops("10")
+	fee.Levels.MedianLevel = drops("128000")
+	fee.Levels.MinimumLevel = drops("256")
+	fee.Levels.OpenLedgerLevel = drops("256")
+	fee.Levels.ReferenceLevel = drops("256")
+	return fee
+}
@@ -0,0 +1,89 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCompleteLedgers(t *testing.T) {
+	ledgers, err := client.ParseCompleteLedgers("32570-6595042,6595044,6595050-6595060")
+	assert.Nil(t, err)
+	assert.Equal(t, client.CompleteLedgers{
+		{Start: 32570, End: 6595042}, {Start: 6595044, End: 6595044}, {Start: 6595050, End: 6595060}}, ledgers)
+	assert.True(t, ledgers.Contains(32570))
+	assert.True(t, ledgers.Contains(6595044))
+	assert.False(t, ledgers.Contains(6595043))
+	assert.False(t, ledgers.Contains(32569))
+	assert.Equal(t, "32570-6595042,6595044,6595050-6595060", ledgers.String())
+
+	ledgers, err = client.ParseCompleteLedgers("empty")
+	assert.Nil(t, err)
+	assert.False(t, ledgers.Contains(1))
+
+	_, err = client.ParseCompleteLedgers("10-1")
+	assert.NotNil(t, err)
+	_, err = client.ParseCompleteLedgers("a-b")
+	assert.NotNil(t, err)
+
+	info := &client.ServerInfo{}
+	assert.Nil(t, json.Unmarshal([]byte(`{"complete_ledgers":"1-5","server_state":"full","validated_ledger":{"seq":5}}`), info))
+	assert.True(t, info.Synced())
+	assert.True(t, info.HasLedger(5))
+}
+
+func TestServerInfo(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+	s.CloseLedger()
+
+	info, err := c.GetServerInfo()
+	assert.Nil(t, err)
+	assert.Equal(t, "full", info.ServerState)
+	assert.True(t, info.Synced())
+	assert.Equal(t, "1-2", info.CompleteLedgers.String())
+	assert.Equal(t, uint32(2), info.ValidatedLedger.Seq)
+	assert.Equal(t, 1.0, info.ValidatedLedger.ReserveBaseXrp)
+
+	state, err := c.GetServerState()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(256), state.LoadFactor)
+	assert.Equal(t, uint64(200000), state.ValidatedLedger.ReserveInc)
+	assert.Equal(t, s.ValidatedLedger().Hash.String(), state.ValidatedLedger.Hash)
+
+	ok, err := c.HasLedger(2)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+	ok, err = c.HasLedger(3)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+
+	s.SetServerState("syncing")
+	ok, err = c.HasLedger(2)
+	assert.Nil(t, err)
+	assert.False(t, ok)
+	info, err = c.GetServerInfo()
+	assert.Nil(t, err)
+	assert.False(t, info.Synced())
+	assert.Nil(t, info.ValidatedLedger)
+}
@@ -0,0 +1,487 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func newAccount(t *testing.T, passphrase string) (*types.Account, string) {
+	seed, err := crypto.GenerateFamilySeed(passphrase)
+	assert.Nil(t, err)
+	account, err := types.ImportAccount(seed.String())
+	assert.Nil(t, err)
+	return account, seed.String()
+}
+
+// submitValidated autofill tx, sign it with account and submit it, then close the ledger and return the
+// result of tx in the validated ledger. The test stops when tx can not be submitted
+func submitValidated(t *testing.T, s *Server, c *client.RpcClient, account *types.Account, tx data.Transaction) string {
+	t.Helper()
+	if !assert.Nil(t, c.Autofill(tx, 0)) {
+		t.FailNow()
+	}
+	txBlob, err := account.SignTx(tx)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	res, err := c.SubmitTx(txBlob)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	s.CloseLedger()
+	result, err := c.GetTx(res.Result.TxJson.Hash, client.LedgerValidated)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return result.MetaData.TransactionResult.String()
+}
+
+func newAmount(t *testing.T, value string) data.Amount {
+	t.Helper()
+	amount, err := data.NewAmount(value)
+	if !assert.Nil(t, err) {
+		t.FailNow()
+	}
+	return *amount
+}
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	multisig, _ := newAccount(t, "multisig")
+	_, err := s.FundAccount(multisig.Account.String(), 100000000)
+	assert.Nil(t, err)
+
+	height, err := c.GetCurrentHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), height)
+
+	info, err := c.GetAccountInfo(multisig.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), *info.AccountData.Sequence)
+
+	fee, err := c.GetFee()
+	assert.Nil(t, err)
+	assert.Equal(t, "10", engine.DropsString(&fee.Drops.BaseFee))
+
+	destination, _ := newAccount(t, "destination")
+	payment := &types.MultisignPayment{
+		TransactionType: "Payment",
+		Account:         multisig.Account.String(),
+		Destination:     destination.Account.String(),
+		Amount:          "1000000",
+		Fee:             "30",
+		Sequence:        *info.AccountData.Sequence,
+	}
+	for _, passphrase := range []string{"signer1", "signer2"} {
+		signer, secret := newAccount(t, passphrase)
+		signRes, err := c.SignFor(signer.Account.String(), secret, payment)
+		assert.Nil(t, err)
+		assert.Equal(t, "success", signRes.Result.Status)
+		payment = signRes.Result.TxJson
+	}
+	assert.Equal(t, 2, len(payment.Signers))
+
+	submitRes, err := c.SubmitMultisigned(payment)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitRes.Result.EngineResult)
+	submitRes, err = c.SubmitMultisigned(payment)
+	assert.Nil(t, err)
+	assert.Equal(t, "tefPAST_SEQ", submitRes.Result.EngineResult)
+
+	ledger := s.CloseLedger()
+	assert.Equal(t, uint32(2), ledger.LedgerSequence)
+	assert.Nil(t, types.VerifyLedgerChain(s.ledgerByIndex(1), ledger))
+
+	hash := submitRes.Result.TxJson.Hash
+	tx, err := c.GetTx(hash)
+	assert.Nil(t, err)
+	assert.True(t, tx.Validated)
+	assert.Equal(t, uint32(2), tx.LedgerSequence)
+	assert.True(t, tx.MetaData.TransactionResult.Success())
+
+	proof, header, err := c.GetTransactionProof(2, hash)
+	assert.Nil(t, err)
+	assert.Equal(t, ledger.LedgerHeader, *header)
+	assert.Nil(t, proof.Verify(header.TransactionHash))
+
+	res, err := c.GetLedger(2)
+	assert.Nil(t, err)
+	assert.Equal(t, ledger.Hash, res.Ledger.Hash)
+	assert.Equal(t, 1, len(res.Ledger.Transactions))
+
+	info, err = c.GetAccountInfo(destination.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, "1000000", engine.DropsString(info.AccountData.Balance))
+	info, err = c.GetAccountInfo(multisig.Account.String())
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), *info.AccountData.Sequence)
+	assert.Equal(t, "98999970", engine.DropsString(info.AccountData.Balance))
+}
+
+func TestServerHandle(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	_, err := c.GetTxBinary(data.Hash256{}.String())
+	assert.NotNil(t, err)
+
+	s.Handle("ledger_closed", func(json.RawMessage) (interface{}, error) {
+		return nil, ErrLedgerNotFound
+	})
+	_, err = c.GetCurrentHeight()
+	assert.NotNil(t, err)
+	assert.Equal(t, 2, len(s.Requests()))
+	assert.Equal(t, "ledger_closed", s.Requests()[1].Method)
+}
+
+func TestGetVerifiedLedger(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	ledger, err := c.GetVerifiedLedger(1)
+	assert.Nil(t, err)
+	assert.Equal(t, s.ValidatedLedger().Hash, ledger.Ledger.Hash)
+	// the error of the server is returned rather than a hash mismatch
+	_, err = c.GetVerifiedLedger(5)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), ErrLedgerNotFound.Name)
+
+	empty := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("{}"))
+	}))
+	defer empty.Close()
+	_, err = client.NewRpcClient().SetAddress(empty.URL).GetVerifiedLedger(1)
+	assert.NotNil(t, err)
+}
+
+func TestServerErr(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	assert.Equal(t, "tefINTERNAL", engine.Result("tecUNKNOWN").String())
+	// results the library does not know are still reported by name
+	for _, token := range []string{"tefBAD_SIGNATURE", "tefBAD_QUORUM", "tesSUCCESS"} {
+		assert.Equal(t, token, submitResponse(engine.Result(token), nil, "")["engine_result"])
+	}
+	assert.Nil(t, s.Err())
+	s.lock.Lock()
+	s.err = errors.New("CloseLedger: cannot encode tx")
+	s.lock.Unlock()
+	assert.NotNil(t, s.Err())
+	_, err := c.GetCurrentHeight()
+	assert.NotNil(t, err)
+	err = c.Call(context.Background(), client.RPC_LEDGER_CLOSED, nil, nil)
+	assert.NotNil(t, err)
+	assert.Contains(t, err.Error(), "cannot encode tx")
+}
+
+func TestCall(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	s.Handle("server_state", func(params json.RawMessage) (interface{}, error) {
+		return map[string]interface{}{"state": map[string]interface{}{"server_state": "full"}, "params": params}, nil
+	})
+	result := &struct {
+		State struct {
+			ServerState string `json:"server_state"`
+		} `json:"state"`
+		Params map[string]interface{} `json:"params"`
+		Status string                 `json:"status"`
+	}{}
+	err := c.Call(context.Background(), "server_state", map[string]interface{}{"counters": true}, result)
+	assert.Nil(t, err)
+	assert.Equal(t, "full", result.State.ServerState)
+	assert.Equal(t, true, result.Params["counters"])
+	assert.Equal(t, "success", result.Status)
+
+	height := &struct {
+		LedgerIndex uint32 `json:"ledger_index"`
+	}{}
+	assert.Nil(t, c.Call(context.Background(), client.RPC_LEDGER_CLOSED, nil, height))
+	assert.Equal(t, uint32(1), height.LedgerIndex)
+
+	err = c.Call(context.Background(), "no_such_method", nil, nil)
+	rpcErr, ok := err.(*client.RpcError)
+	assert.True(t, ok)
+	assert.Equal(t, ErrUnknownCmd.Name, rpcErr.Name)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	assert.NotNil(t, c.Call(ctx, client.RPC_LEDGER_CLOSED, nil, height))
+}
+
+type flakyTransport struct {
+	failures  int
+	attempts  int
+	transport http.RoundTripper
+}
+
+func (this *flakyTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	this.attempts++
+	if this.attempts <= this.failures {
+		return nil, errors.New("connection reset")
+	}
+	return this.transport.RoundTrip(req)
+}
+
+func TestRetries(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	transport := &flakyTransport{failures: 2, transport: http.DefaultTransport}
+	c := s.Client().SetHttpClient(&http.Client{Transport: transport}).SetRetries(2, time.Millisecond)
+	height, err := c.GetCurrentHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), height)
+	assert.Equal(t, 3, transport.attempts)
+
+	transport.attempts, transport.failures = 0, 5
+	err = c.Call(context.Background(), client.RPC_LEDGER_CLOSED, nil, nil)
+	assert.True(t, errors.As(err, &client.PostErr{}))
+	assert.Equal(t, 3, transport.attempts)
+}
+
+func TestBatch(t *testing.T) {
+	for _, batch := range []bool{true, false} {
+		s := NewServer()
+		s.SetBatch(batch)
+		_, err := s.FundAccount(goldenAccount, 100000000)
+		assert.Nil(t, err)
+		c := s.Client().SetBatchParallelism(2)
+
+		height := &struct {
+			LedgerIndex uint32 `json:"ledger_index"`
+		}{}
+		info := &struct {
+			AccountData struct {
+				Account string
+			} `json:"account_data"`
+		}{}
+		items := []*client.BatchItem{
+			{Method: client.RPC_LEDGER_CLOSED, Result: height},
+			{Method: client.RPC_ACCOUNT_INFO, Params: map[string]string{"account": goldenAccount}, Result: info},
+			{Method: client.RPC_ACCOUNT_INFO, Params: map[string]string{"account": "rT4vRkeJsgaq7t6TVJJPsbrQp5oKMGRfN"}},
+			{Method: client.RPC_TX, Params: map[string]string{"transaction": data.Hash256{}.String()}},
+		}
+		assert.Nil(t, c.Batch(context.Background(), items))
+		assert.Nil(t, items[0].Err)
+		assert.Equal(t, uint32(1), height.LedgerIndex)
+		assert.Nil(t, items[1].Err)
+		assert.Equal(t, goldenAccount, info.AccountData.Account)
+		assert.Equal(t, ErrActNotFound, items[2].Err)
+		assert.Equal(t, ErrTxNotFound, items[3].Err)
+
+		items = items[:1]
+		assert.Nil(t, c.Batch(context.Background(), items))
+		assert.Nil(t, items[0].Err)
+		if batch {
+			assert.Equal(t, 5, len(s.Requests()))
+		} else {
+			// the first batch is refused, later ones go straight to concurrent requests
+			assert.Equal(t, 6, len(s.Requests()))
+			assert.Equal(t, client.RPC_BATCH, s.Requests()[0].Method)
+		}
+		s.Close()
+	}
+
+	// a busy server does not disable batch, only an unknown method does
+	s := NewServer()
+	defer s.Close()
+	s.SetBatch(false)
+	busy := true
+	s.Handle(client.RPC_BATCH, func(json.RawMessage) (interface{}, error) {
+		if busy {
+			busy = false
+			return nil, &RpcError{Name: "tooBusy", Code: 9, Message: "The server is too busy to help you now."}
+		}
+		return nil, ErrUnknownCmd
+	})
+	c := s.Client()
+	items := []*client.BatchItem{{Method: client.RPC_LEDGER_CLOSED}}
+	assert.NotNil(t, c.Batch(context.Background(), items))
+	assert.Equal(t, 1, len(s.Requests()))
+	assert.Nil(t, c.Batch(context.Background(), items))
+	assert.Nil(t, items[0].Err)
+	assert.Equal(t, 3, len(s.Requests()))
+	assert.Equal(t, client.RPC_BATCH, s.Requests()[1].Method)
+}
+
+type statusTransport struct {
+	statusCodes []int
+	attempts    int
+	transport   http.RoundTripper
+}
+
+func (this *statusTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	this.attempts++
+	if this.attempts <= len(this.statusCodes) {
+		code := this.statusCodes[this.attempts-1]
+		return &http.Response{
+			StatusCode: code,
+			Header:     http.Header{"Retry-After": []string{"0"}},
+			Body:       ioutil.NopCloser(strings.NewReader(http.StatusText(code))),
+			Request:    req,
+		}, nil
+	}
+	return this.transport.RoundTrip(req)
+}
+
+func TestBackoff(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	slowDowns := 2
+	s.Handle(client.RPC_LEDGER_CLOSED, func(params json.RawMessage) (interface{}, error) {
+		if slowDowns > 0 {
+			slowDowns--
+			return nil, &RpcError{Name: "slowDown", Code: 10, Message: "You are placing too much load on the server."}
+		}
+		return s.ledgerClosed(params)
+	})
+	c := s.Client().SetBackoff(time.Millisecond, 10*time.Millisecond, 2)
+	height, err := c.GetCurrentHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), height)
+	assert.Equal(t, 3, len(s.Requests()))
+
+	slowDowns = 3
+	err = c.Call(context.Background(), client.RPC_LEDGER_CLOSED, nil, nil)
+	assert.True(t, errors.As(err, &client.ThrottleErr{}))
+
+	transport := &statusTransport{statusCodes: []int{http.StatusTooManyRequests, http.StatusServiceUnavailable}, transport: http.DefaultTransport}
+	c.SetHttpClient(&http.Client{Transport: transport})
+	height, err = c.GetCurrentHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), height)
+	assert.Equal(t, 3, transport.attempts)
+}
+
+func TestRateLimit(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client().SetRateLimit(100, 1)
+	start := time.Now()
+	for i := 0; i < 5; i++ {
+		_, err := c.GetCurrentHeight()
+		assert.Nil(t, err)
+	}
+	assert.True(t, time.Since(start) >= 35*time.Millisecond)
+
+	var lock sync.Mutex
+	inFlight, maxInFlight := 0, 0
+	s.Handle(client.RPC_LEDGER_CLOSED, func(params json.RawMessage) (interface{}, error) {
+		lock.Lock()
+		inFlight++
+		if inFlight > maxInFlight {
+			maxInFlight = inFlight
+		}
+		lock.Unlock()
+		time.Sleep(10 * time.Millisecond)
+		lock.Lock()
+		inFlight--
+		lock.Unlock()
+		return s.ledgerClosed(params)
+	})
+	c = s.Client().SetMaxInFlight(2)
+	wg := &sync.WaitGroup{}
+	for i := 0; i < 6; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.GetCurrentHeight()
+			assert.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	assert.Equal(t, 2, maxInFlight)
+}
+
+type hookKey struct{}
+
+type recordingHook struct {
+	name  string
+	order *[]string
+	infos []client.RequestInfo
+}
+
+func (this *recordingHook) Before(ctx context.Context, info *client.RequestInfo) context.Context {
+	*this.order = append(*this.order, "before "+this.name)
+	return context.WithValue(ctx, hookKey{}, this.name)
+}
+
+func (this *recordingHook) After(ctx context.Context, info *client.RequestInfo) {
+	*this.order = append(*this.order, "after "+ctx.Value(hookKey{}).(string))
+	this.infos = append(this.infos, *info)
+}
+
+func TestHook(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	var order []string
+	first, second := &recordingHook{name: "first", order: &order}, &recordingHook{name: "second", order: &order}
+	c := s.Client().AddHook(first).AddHook(second)
+
+	_, err := c.GetCurrentHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, []string{"before first", "before second", "after second", "after first"}, order)
+	info := first.infos[0]
+	assert.Equal(t, client.RPC_LEDGER_CLOSED, info.Method)
+	assert.Equal(t, s.URL, info.Endpoint)
+	assert.True(t, info.Duration > 0)
+	assert.True(t, info.RequestBytes > 0)
+	assert.True(t, info.ResponseBytes > 0)
+	assert.Equal(t, client.ErrorClassNone, info.ErrorClass)
+
+	err = c.Call(context.Background(), client.RPC_ACCOUNT_INFO, map[string]string{"account": goldenAccount}, nil)
+	assert.NotNil(t, err)
+	info = first.infos[1]
+	assert.Nil(t, info.Err)
+	assert.Equal(t, client.ErrorClassRpc, info.ErrorClass)
+	assert.Equal(t, ErrActNotFound.Name, info.RpcError)
+
+	s.Close()
+	_, err = c.GetCurrentHeight()
+	assert.NotNil(t, err)
+	info = first.infos[2]
+	assert.Equal(t, client.ErrorClassTransport, info.ErrorClass)
+	assert.Equal(t, err.Error(), "GetCurrentHeight: send req err: "+info.Err.Error())
+}
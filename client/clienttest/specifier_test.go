@@ -0,0 +1,113 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestLedgerSpecifier(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	cache := client.NewLRUCache(16)
+	c := s.Client().SetCache(cache)
+
+	account, _ := newAccount(t, "account")
+	_, err := s.FundAccount(account.Account.String(), 100000000)
+	assert.Nil(t, err)
+	genesis := s.ValidatedLedger()
+	s.CloseLedger()
+
+	height, err := c.GetValidatedHeight()
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), height)
+
+	ledger, err := c.GetLedgerAt(client.LedgerAtHash(genesis.Hash.String()))
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), ledger.Ledger.LedgerSequence)
+	ledger, err = c.GetLedgerAt(client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), ledger.Ledger.LedgerSequence)
+	binary, err := c.GetLedgerBinaryAt(client.LedgerClosed)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2), binary.Result.LedgerIndex)
+	_, err = c.GetLedgerBinaryAt(client.LedgerAtIndex(3))
+	assert.NotNil(t, err)
+
+	// moving ledgers are never served from the cache
+	requests := len(s.Requests())
+	_, err = c.GetLedgerAt(client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, requests+1, len(s.Requests()))
+
+	info, err := c.GetAccountInfo(account.Account.String(), client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, "100000000", engine.DropsString(info.AccountData.Balance))
+	req, err := json.Marshal(s.Requests()[len(s.Requests())-1])
+	assert.Nil(t, err)
+	assert.Contains(t, string(req), `"ledger_index":"validated"`)
+
+	tx := map[string]interface{}{"TransactionType": "Payment", "hash": "01", "validated": false}
+	s.Handle(client.RPC_TX, func(params json.RawMessage) (interface{}, error) {
+		return tx, nil
+	})
+	_, err = c.GetTx("01")
+	assert.Nil(t, err)
+	_, err = c.GetTx("01", client.LedgerCurrent)
+	assert.Nil(t, err)
+	_, err = c.GetTx("01", client.LedgerClosed)
+	assert.NotNil(t, err)
+	_, err = c.GetTx("01", client.LedgerValidated)
+	assert.NotNil(t, err)
+
+	tx["validated"] = true
+	tx["ledger_index"] = 2
+	_, err = c.GetTx("01", client.LedgerValidated)
+	assert.Nil(t, err)
+	_, err = c.GetTx("01", client.LedgerAtIndex(2))
+	assert.Nil(t, err)
+	_, err = c.GetTx("01", client.LedgerAtIndex(1))
+	assert.NotNil(t, err)
+	_, err = c.GetTx("01", client.LedgerSpecifier{LedgerIndex: 2})
+	assert.Nil(t, err)
+	_, err = c.GetTx("01", client.LedgerSpecifier{LedgerIndex: int64(1)})
+	assert.NotNil(t, err)
+	_, err = c.GetTx("01", client.LedgerAtHash(genesis.Hash.String()))
+	assert.NotNil(t, err)
+	_, err = c.GetTx("01", client.LedgerAtHash(s.ValidatedLedger().Hash.String()))
+	assert.Nil(t, err)
+	// the validated tx is cached, ask again without the cache
+	tx["validated"] = false
+	_, err = s.Client().GetTx("01", client.LedgerAtHash(s.ValidatedLedger().Hash.String()))
+	assert.NotNil(t, err)
+
+	// a specifier which can not select a tx is rejected before the tx is requested
+	requests = len(s.Requests())
+	for _, ledger := range []client.LedgerSpecifier{{LedgerIndex: "latest"}, {LedgerIndex: -1}, {LedgerIndex: 2.5}} {
+		_, err = c.GetTx("01", ledger)
+		assert.NotNil(t, err)
+		_, err = c.GetTxBinary("01", ledger)
+		assert.NotNil(t, err)
+	}
+	assert.Equal(t, requests, len(s.Requests()))
+}
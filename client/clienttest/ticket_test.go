@@ -0,0 +1,171 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package clienttest
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/polynetwork/ripple-sdk/client/clienttest/internal/engine"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketPool(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	owner, _ := newAccount(t, "owner")
+	address := owner.Account.String()
+	_, err := s.FundAccount(address, 100000000)
+	assert.Nil(t, err)
+	sequence := *s.Account(address).Sequence
+	ticketCreate, err := types.GenerateTicketCreate(owner.Account, 3, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, c.Autofill(ticketCreate, 0))
+	txBlob, err := owner.SignTx(ticketCreate)
+	assert.Nil(t, err)
+	res, err := c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	assert.True(t, res.Accepted())
+	s.CloseLedger()
+	tickets, err := c.GetTickets(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{sequence + 1, sequence + 2, sequence + 3}, tickets)
+	assert.Equal(t, sequence+4, *s.Account(address).Sequence)
+
+	signers := make([]*types.Account, 2)
+	var entries []types.SignerEntry
+	for i, passphrase := range []string{"signer1", "signer2"} {
+		signers[i], _ = newAccount(t, passphrase)
+		entries = append(entries, types.NewSignerEntry(signers[i].Account, 1))
+	}
+	signerListSet, err := types.GenerateSignerListSet(owner.Account, 2, entries, data.Value{}, 0)
+	assert.Nil(t, err)
+	assert.Nil(t, c.Autofill(signerListSet, 0))
+	txBlob, err = owner.SignTx(signerListSet)
+	assert.Nil(t, err)
+	_, err = c.SubmitTx(txBlob)
+	assert.Nil(t, err)
+	s.CloseLedger()
+
+	pool, err := c.NewTicketPool(address)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, pool.Available())
+	destination, _ := newAccount(t, "destination")
+	amount, err := data.NewAmount("1000000")
+	assert.Nil(t, err)
+	payments := make([]*types.TicketPayment, 2)
+	for i := range payments {
+		payments[i], err = pool.NewPayment(destination.Account.String(), *amount, len(signers))
+		assert.Nil(t, err)
+		assert.Equal(t, sequence+1+uint32(i), payments[i].TicketSequence)
+		assert.Equal(t, uint32(0), payments[i].Sequence)
+		assert.Equal(t, "30", engine.DropsString(&payments[i].Fee))
+	}
+	ticket, err := pool.Acquire()
+	assert.Nil(t, err)
+	_, err = pool.Acquire()
+	assert.NotNil(t, err)
+	pool.Release(ticket)
+	assert.Equal(t, 1, pool.Available())
+
+	// a tx failing with a tef result does not consume its ticket, a tec one does
+	failed, err := pool.NewPayment(destination.Account.String(), *amount, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, 0, pool.Available())
+	failedRaw, err := types.SerializeRawMultiSignTransaction(failed)
+	assert.Nil(t, err)
+	failedTx, err := types.DeserializeRawMultiSignTransaction(failedRaw)
+	assert.Nil(t, err)
+	failedSigned, err := signers[0].MultiSignTransaction(failedRaw)
+	assert.Nil(t, err)
+	types.AddSigners(failedTx, failedSigned.GetBase().Signers...)
+	rejected, err := c.SubmitMultisignedTx(failedTx)
+	assert.Nil(t, err)
+	assert.Equal(t, "tefBAD_QUORUM", rejected.Result.EngineResult)
+	pool.Failed(failed.TicketSequence, "tecUNFUNDED_PAYMENT")
+	assert.Equal(t, 0, pool.Available())
+	pool.Failed(failed.TicketSequence, rejected.Result.EngineResult)
+	assert.Equal(t, 1, pool.Available())
+	s.CloseLedger()
+	assert.Nil(t, pool.Sync())
+	assert.Equal(t, 1, pool.Available())
+
+	// the payments are signed in parallel and submitted out of order
+	for i := len(payments) - 1; i >= 0; i-- {
+		rawTx, err := types.SerializeRawMultiSignTransaction(payments[i])
+		assert.Nil(t, err)
+		tx, err := types.DeserializeRawMultiSignTransaction(rawTx)
+		assert.Nil(t, err)
+		for _, signer := range signers {
+			signed, err := signer.MultiSignTransaction(rawTx)
+			assert.Nil(t, err)
+			types.AddSigners(tx, signed.GetBase().Signers...)
+		}
+		res, err := c.SubmitMultisignedTx(tx)
+		assert.Nil(t, err)
+		assert.Equal(t, "tesSUCCESS", res.Result.EngineResult)
+		res, err = c.SubmitMultisignedTx(tx)
+		assert.Nil(t, err)
+		assert.Equal(t, "tefPAST_SEQ", res.Result.EngineResult)
+	}
+	s.CloseLedger()
+
+	assert.Nil(t, pool.Sync())
+	assert.Equal(t, 1, pool.Available())
+	tickets, err = c.GetTickets(address, client.LedgerValidated)
+	assert.Nil(t, err)
+	assert.Equal(t, []uint32{sequence + 3}, tickets)
+	assert.Equal(t, sequence+5, *s.Account(address).Sequence)
+	assert.Equal(t, int64(2000000), engine.Drops(s.Account(destination.Account.String()).Balance))
+	// the signer list and the ticket left
+	assert.Equal(t, uint32(2), *s.Account(address).OwnerCount)
+
+	// refill the pool once its last ticket is taken
+	_, err = pool.Acquire()
+	assert.Nil(t, err)
+	_, err = pool.Acquire()
+	assert.NotNil(t, err)
+	_, err = pool.NewTicketCreate(0, len(signers))
+	assert.NotNil(t, err)
+	refill, err := pool.NewTicketCreate(2, len(signers))
+	assert.Nil(t, err)
+	assert.Equal(t, sequence+5, refill.Sequence)
+	assert.Equal(t, "30", engine.DropsString(&refill.Fee))
+	rawTx, err := types.SerializeRawMultiSignTransaction(refill)
+	assert.Nil(t, err)
+	tx, err := types.DeserializeRawMultiSignTransaction(rawTx)
+	assert.Nil(t, err)
+	for _, signer := range signers {
+		signed, err := signer.MultiSignTransaction(rawTx)
+		assert.Nil(t, err)
+		types.AddSigners(tx, signed.GetBase().Signers...)
+	}
+	submitted, err := c.SubmitMultisignedTx(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, "tesSUCCESS", submitted.Result.EngineResult)
+	s.CloseLedger()
+	assert.Nil(t, pool.Sync())
+	assert.Equal(t, 2, pool.Available())
+	ticket, err = pool.Acquire()
+	assert.Nil(t, err)
+	assert.Equal(t, sequence+6, ticket)
+}
@@ -17,7 +17,13 @@
 
 package client
 
-import "github.com/polynetwork/ripple-sdk/types"
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
 
 const (
 	RPC_TX                 = "tx"
@@ -25,8 +31,19 @@ const (
 	RPC_ACCOUNT_INFO       = "account_info"
 	RPC_SIGN_FOR           = "sign_for"
 	RPC_SUBMIT_MULTISIGNED = "submit_multisigned"
+	RPC_SUBMIT             = "submit"
 	RPC_LEDGER_CLOSED      = "ledger_closed"
 	RPC_LEDGER             = "ledger"
+	RPC_LEDGER_ENTRY       = "ledger_entry"
+	RPC_LEDGER_DATA        = "ledger_data"
+	RPC_MANIFEST           = "manifest"
+	RPC_BATCH              = "batch"
+	RPC_SERVER_INFO        = "server_info"
+	RPC_SERVER_STATE       = "server_state"
+	RPC_ACCOUNT_OBJECTS    = "account_objects"
+	RPC_ACCOUNT_CHANNELS   = "account_channels"
+	RPC_ACCOUNT_OFFERS     = "account_offers"
+	RPC_BOOK_OFFERS        = "book_offers"
 )
 
 type JsonRpcRequest struct {
@@ -34,6 +51,35 @@ type JsonRpcRequest struct {
 	Params []interface{} `json:"params"`
 }
 
+// RpcError is the error rippled reports in the result object of a failed request
+type RpcError struct {
+	Name    string `json:"error"`
+	Code    int    `json:"error_code"`
+	Message string `json:"error_message"`
+}
+
+func (err *RpcError) Error() string {
+	return fmt.Sprintf("%s: %s", err.Name, err.Message)
+}
+
+// BatchItem is one method call of RpcClient.Batch, Result is decoded in place and Err holds the
+// error of this call only
+type BatchItem struct {
+	Method string
+	Params interface{}
+	Result interface{}
+	Err    error
+}
+
+type rpcResp struct {
+	Result json.RawMessage `json:"result"`
+}
+
+type rpcStatus struct {
+	Status string `json:"status"`
+	RpcError
+}
+
 type txReqParam struct {
 	Transaction string `json:"transaction"`
 	Binary      bool   `json:"binary"`
@@ -43,6 +89,7 @@ type accountInfoReqParam struct {
 	Account string `json:"account"`
 	Strict  bool   `json:"strict"`
 	Queue   bool   `json:"queue"`
+	LedgerSpecifier
 }
 
 type sigForReqParam struct {
@@ -75,20 +122,281 @@ type submitTxReq struct {
 	TxBlob string `json:"tx_blob"`
 }
 
+type SubmitRes struct {
+	Result struct {
+		Status              string          `json:"status"`
+		TxBlob              string          `json:"tx_blob"`
+		TxJson              *types.Response `json:"tx_json"`
+		ErrorMessage        string          `json:"error_message"`
+		EngineResult        string          `json:"engine_result"`
+		EngineResultCode    int             `json:"engine_result_code"`
+		EngineResultMessage string          `json:"engine_result_message"`
+	} `json:"result"`
+}
+
+// Accepted return whether the tx was applied to the open ledger or queued, it is final only once validated
+func (this *SubmitRes) Accepted() bool {
+	return this.Result.EngineResult == "tesSUCCESS" || this.Result.EngineResult == "terQUEUED"
+}
+
 type submitMultisignedTxReq struct {
 	TxJson *types.MultisignPayment `json:"tx_json"`
 }
 
+type submitMultisignedTxJsonReq struct {
+	TxJson map[string]interface{} `json:"tx_json"`
+}
+
 type heightResp struct {
 	Result struct {
-		LedgerHash  string `json:"ledger_hash"`
-		LedgerIndex uint32 `json:"ledger_index"`
-		Status      string `json:"status"`
+		LedgerHash   string `json:"ledger_hash"`
+		LedgerIndex  uint32 `json:"ledger_index"`
+		Validated    bool   `json:"validated"`
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
 	} `json:"result"`
 }
 
 type ledgerReqParam struct {
-	LedgerIndex  uint32 `json:"ledger_index"`
-	Transactions bool   `json:"transactions"`
-	Expand       bool   `json:"expand"`
+	LedgerSpecifier
+	Transactions bool `json:"transactions"`
+	Expand       bool `json:"expand"`
+	Binary       bool `json:"binary"`
+}
+
+type BinaryTx struct {
+	TxBlob string `json:"tx_blob"`
+	Meta   string `json:"meta"`
+}
+
+type BinaryTxRes struct {
+	Result struct {
+		Tx           string `json:"tx"`
+		Meta         string `json:"meta"`
+		Hash         string `json:"hash"`
+		LedgerIndex  uint32 `json:"ledger_index"`
+		Validated    bool   `json:"validated"`
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"result"`
+}
+
+type BinaryLedgerRes struct {
+	Result struct {
+		Ledger struct {
+			LedgerData   string      `json:"ledger_data"`
+			Closed       bool        `json:"closed"`
+			Transactions []*BinaryTx `json:"transactions"`
+		} `json:"ledger"`
+		LedgerHash   string `json:"ledger_hash"`
+		LedgerIndex  uint32 `json:"ledger_index"`
+		Validated    bool   `json:"validated"`
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"result"`
+}
+
+type ledgerEntryReqParam struct {
+	Index string `json:"index"`
+	LedgerSpecifier
+	Binary bool `json:"binary"`
+}
+
+type LedgerEntryRes struct {
+	Result struct {
+		Index        string `json:"index"`
+		LedgerHash   string `json:"ledger_hash"`
+		LedgerIndex  uint32 `json:"ledger_index"`
+		NodeBinary   string `json:"node_binary"`
+		Validated    bool   `json:"validated"`
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"result"`
+}
+
+type ledgerDataReqParam struct {
+	LedgerSpecifier
+	Binary bool   `json:"binary"`
+	Marker string `json:"marker,omitempty"`
+}
+
+type BinaryLedgerEntry struct {
+	Data  string `json:"data"`
+	Index string `json:"index"`
+}
+
+type LedgerDataRes struct {
+	Result struct {
+		Ledger *struct {
+			LedgerData string `json:"ledger_data"`
+			Closed     bool   `json:"closed"`
+		} `json:"ledger,omitempty"`
+		LedgerHash   string               `json:"ledger_hash"`
+		LedgerIndex  uint32               `json:"ledger_index"`
+		Marker       string               `json:"marker"`
+		State        []*BinaryLedgerEntry `json:"state"`
+		Validated    bool                 `json:"validated"`
+		Status       string               `json:"status"`
+		ErrorMessage string               `json:"error_message"`
+	} `json:"result"`
+}
+
+type manifestReqParam struct {
+	PublicKey string `json:"public_key"`
+}
+
+type ManifestRes struct {
+	Result struct {
+		Details *struct {
+			Domain       string `json:"domain"`
+			EphemeralKey string `json:"ephemeral_key"`
+			MasterKey    string `json:"master_key"`
+			Seq          uint32 `json:"seq"`
+		} `json:"details,omitempty"`
+		Manifest     string `json:"manifest"`
+		Requested    string `json:"requested"`
+		Status       string `json:"status"`
+		ErrorMessage string `json:"error_message"`
+	} `json:"result"`
+}
+
+type accountObjectsReqParam struct {
+	Account string `json:"account"`
+	Type    string `json:"type,omitempty"`
+	Limit   uint32 `json:"limit,omitempty"`
+	Marker  string `json:"marker,omitempty"`
+	LedgerSpecifier
+}
+
+type AccountObjectsRes struct {
+	Result struct {
+		Account            string                `json:"account"`
+		AccountObjects     data.LedgerEntrySlice `json:"account_objects"`
+		LedgerHash         string                `json:"ledger_hash"`
+		LedgerIndex        uint32                `json:"ledger_index"`
+		LedgerCurrentIndex uint32                `json:"ledger_current_index"`
+		Limit              uint32                `json:"limit"`
+		Marker             string                `json:"marker"`
+		Validated          bool                  `json:"validated"`
+		Status             string                `json:"status"`
+		ErrorMessage       string                `json:"error_message"`
+	} `json:"result"`
+}
+
+type accountChannelsReqParam struct {
+	Account            string `json:"account"`
+	DestinationAccount string `json:"destination_account,omitempty"`
+	Limit              uint32 `json:"limit,omitempty"`
+	Marker             string `json:"marker,omitempty"`
+	LedgerSpecifier
+}
+
+// AccountChannel is a payment channel as account_channels reports it, Amount is the XRP the channel
+// holds and Balance the XRP it has delivered, both in drops
+type AccountChannel struct {
+	ChannelId          data.Hash256   `json:"channel_id"`
+	Account            data.Account   `json:"account"`
+	DestinationAccount data.Account   `json:"destination_account"`
+	Amount             uint64         `json:"amount,string"`
+	Balance            uint64         `json:"balance,string"`
+	SettleDelay        uint32         `json:"settle_delay"`
+	PublicKey          string         `json:"public_key,omitempty"`
+	PublicKeyHex       data.PublicKey `json:"public_key_hex,omitempty"`
+	Expiration         uint32         `json:"expiration,omitempty"`
+	CancelAfter        uint32         `json:"cancel_after,omitempty"`
+	SourceTag          uint32         `json:"source_tag,omitempty"`
+	DestinationTag     uint32         `json:"destination_tag,omitempty"`
+}
+
+type AccountChannelsRes struct {
+	Result struct {
+		Account            string            `json:"account"`
+		Channels           []*AccountChannel `json:"channels"`
+		LedgerHash         string            `json:"ledger_hash"`
+		LedgerIndex        uint32            `json:"ledger_index"`
+		LedgerCurrentIndex uint32            `json:"ledger_current_index"`
+		Limit              uint32            `json:"limit"`
+		Marker             string            `json:"marker"`
+		Validated          bool              `json:"validated"`
+		Status             string            `json:"status"`
+		ErrorMessage       string            `json:"error_message"`
+	} `json:"result"`
+}
+
+type accountOffersReqParam struct {
+	Account string `json:"account"`
+	Limit   uint32 `json:"limit,omitempty"`
+	Marker  string `json:"marker,omitempty"`
+	LedgerSpecifier
+}
+
+// AccountOffer is an offer as account_offers reports it, Seq is the sequence of the OfferCreate that
+// placed it, which OfferCancel takes
+type AccountOffer struct {
+	Flags      uint32        `json:"flags"`
+	Seq        uint32        `json:"seq"`
+	TakerGets  data.Amount   `json:"taker_gets"`
+	TakerPays  data.Amount   `json:"taker_pays"`
+	Quality    types.Quality `json:"quality"`
+	Expiration uint32        `json:"expiration,omitempty"`
+}
+
+type AccountOffersRes struct {
+	Result struct {
+		Account            string          `json:"account"`
+		Offers             []*AccountOffer `json:"offers"`
+		LedgerHash         string          `json:"ledger_hash"`
+		LedgerIndex        uint32          `json:"ledger_index"`
+		LedgerCurrentIndex uint32          `json:"ledger_current_index"`
+		Limit              uint32          `json:"limit"`
+		Marker             string          `json:"marker"`
+		Validated          bool            `json:"validated"`
+		Status             string          `json:"status"`
+		ErrorMessage       string          `json:"error_message"`
+	} `json:"result"`
+}
+
+type bookOffersReqParam struct {
+	TakerGets data.Asset `json:"taker_gets"`
+	TakerPays data.Asset `json:"taker_pays"`
+	Taker     string     `json:"taker,omitempty"`
+	Limit     uint32     `json:"limit,omitempty"`
+	Marker    string     `json:"marker,omitempty"`
+	LedgerSpecifier
+}
+
+// BookOffer is an offer of an order book as book_offers reports it. OwnerFunds is what the owner holds
+// of TakerGets, and the funded amounts are set when it does not cover the whole offer
+type BookOffer struct {
+	data.Offer
+	OwnerFunds      string        `json:"owner_funds,omitempty"`
+	Quality         types.Quality `json:"quality"`
+	TakerGetsFunded *data.Amount  `json:"taker_gets_funded,omitempty"`
+	TakerPaysFunded *data.Amount  `json:"taker_pays_funded,omitempty"`
+}
+
+type BookOffersRes struct {
+	Result struct {
+		Offers             []*BookOffer `json:"offers"`
+		LedgerHash         string       `json:"ledger_hash"`
+		LedgerIndex        uint32       `json:"ledger_index"`
+		LedgerCurrentIndex uint32       `json:"ledger_current_index"`
+		Limit              uint32       `json:"limit"`
+		Marker             string       `json:"marker"`
+		Validated          bool         `json:"validated"`
+		Status             string       `json:"status"`
+		ErrorMessage       string       `json:"error_message"`
+	} `json:"result"`
+}
+
+type SignerListRes struct {
+	Result struct {
+		Account        string              `json:"account"`
+		AccountObjects []*types.SignerList `json:"account_objects"`
+		LedgerHash     string              `json:"ledger_hash"`
+		LedgerIndex    uint32              `json:"ledger_index"`
+		Validated      bool                `json:"validated"`
+		Status         string              `json:"status"`
+		ErrorMessage   string              `json:"error_message"`
+	} `json:"result"`
 }
@@ -0,0 +1,114 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// error classes reported to hooks, they are few on purpose to be usable as metric labels
+const (
+	ErrorClassNone      = ""
+	ErrorClassTransport = "transport"
+	ErrorClassThrottled = "throttled"
+	ErrorClassCanceled  = "canceled"
+	ErrorClassRpc       = "rpc"
+	ErrorClassInternal  = "internal"
+)
+
+// RequestInfo describe one rpc request, the fields after RequestBytes are only set when the request is done
+type RequestInfo struct {
+	Method        string
+	Endpoint      string
+	RequestBytes  int
+	Duration      time.Duration
+	ResponseBytes int
+	Err           error
+	ErrorClass    string
+	// RpcError is the error name reported by rippled, e.g. actNotFound, when ErrorClass is ErrorClassRpc
+	RpcError string
+}
+
+// Hook observe every request sent by RpcClient. Before is called before the request is sent and the
+// context it returns is used for the request and passed to After, e.g. to carry a tracing span
+type Hook interface {
+	Before(ctx context.Context, info *RequestInfo) context.Context
+	After(ctx context.Context, info *RequestInfo)
+}
+
+// AddHook register hook for all the following requests, hooks run in the order they are added
+func (this *RpcClient) AddHook(hook Hook) *RpcClient {
+	this.hooks = append(this.hooks, hook)
+	return this
+}
+
+func (this *RpcClient) sendWithHooks(ctx context.Context, method string, data []byte) ([]byte, error) {
+	info := &RequestInfo{Method: method, Endpoint: this.addr, RequestBytes: len(data)}
+	ctxs := make([]context.Context, len(this.hooks))
+	for i, hook := range this.hooks {
+		ctx = hook.Before(ctx, info)
+		ctxs[i] = ctx
+	}
+	start := time.Now()
+	body, err := this.send(ctx, method, data)
+	info.Duration = time.Since(start)
+	info.ResponseBytes = len(body)
+	info.Err = err
+	info.ErrorClass, info.RpcError = classify(body, err)
+	for i := len(this.hooks) - 1; i >= 0; i-- {
+		this.hooks[i].After(ctxs[i], info)
+	}
+	return body, err
+}
+
+func classify(body []byte, err error) (string, string) {
+	switch {
+	case err == nil:
+		if rpcErr := resultError(body); rpcErr != nil {
+			return ErrorClassRpc, rpcErr.Name
+		}
+		return ErrorClassNone, ""
+	case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+		return ErrorClassCanceled, ""
+	case errors.As(err, &ThrottleErr{}):
+		return ErrorClassThrottled, ""
+	case errors.As(err, &PostErr{}):
+		return ErrorClassTransport, ""
+	default:
+		return ErrorClassInternal, ""
+	}
+}
+
+// resultError return the error carried by the result object of body, nil if there is none
+func resultError(body []byte) *RpcError {
+	resp := &rpcResp{}
+	if err := json.Unmarshal(body, resp); err != nil || len(resp.Result) == 0 {
+		return nil
+	}
+	status := &rpcStatus{}
+	if err := json.Unmarshal(resp.Result, status); err != nil {
+		return nil
+	}
+	if status.Status != "error" && status.Name == "" {
+		return nil
+	}
+	return &status.RpcError
+}
@@ -0,0 +1,195 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	DefaultBackoffMin     = 500 * time.Millisecond
+	DefaultBackoffMax     = 30 * time.Second
+	DefaultBackoffRetries = 3
+)
+
+// ThrottleErr is returned when the server still throttles the request after all the backoff retries
+type ThrottleErr struct {
+	StatusCode int
+	Reason     string
+}
+
+func (err ThrottleErr) Error() string {
+	return fmt.Sprintf("request throttled by server, http status: %d, reason: %s", err.StatusCode, err.Reason)
+}
+
+// tokenBucket allow rate requests per second on average and up to burst at once
+type tokenBucket struct {
+	lock   sync.Mutex
+	rate   float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(rate float64, burst int) *tokenBucket {
+	if burst < 1 {
+		burst = 1
+	}
+	return &tokenBucket{rate: rate, burst: float64(burst), tokens: float64(burst), last: time.Now()}
+}
+
+// reserve take a token and return how long to wait before it is available
+func (this *tokenBucket) reserve() time.Duration {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	now := time.Now()
+	this.tokens += now.Sub(this.last).Seconds() * this.rate
+	if this.tokens > this.burst {
+		this.tokens = this.burst
+	}
+	this.last = now
+	this.tokens--
+	if this.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-this.tokens / this.rate * float64(time.Second))
+}
+
+// backoff is shared by all the requests of a client, it grows every time the server throttles
+// and shrinks back on success
+type backoff struct {
+	lock    sync.Mutex
+	min     time.Duration
+	max     time.Duration
+	retries int
+	current time.Duration
+}
+
+func (this *backoff) delay() time.Duration {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return this.current
+}
+
+func (this *backoff) throttled(retryAfter time.Duration) time.Duration {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.current *= 2
+	if this.current < this.min {
+		this.current = this.min
+	}
+	if this.current < retryAfter {
+		this.current = retryAfter
+	}
+	if this.current > this.max {
+		this.current = this.max
+	}
+	return this.current
+}
+
+func (this *backoff) succeeded() {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	this.current /= 2
+	if this.current < this.min {
+		this.current = 0
+	}
+}
+
+// SetRateLimit limit the client to requestsPerSecond on average with bursts up to burst, 0 disable it
+func (this *RpcClient) SetRateLimit(requestsPerSecond float64, burst int) *RpcClient {
+	this.limiter = nil
+	if requestsPerSecond > 0 {
+		this.limiter = newTokenBucket(requestsPerSecond, burst)
+	}
+	return this
+}
+
+// SetMaxInFlight limit the number of concurrent requests of the client, 0 disable it
+func (this *RpcClient) SetMaxInFlight(max int) *RpcClient {
+	this.inFlight = nil
+	if max > 0 {
+		this.inFlight = make(chan struct{}, max)
+	}
+	return this
+}
+
+// SetBackoff set the adaptive backoff used when the server answers slowDown or http 429/503, the delay
+// starts at min, doubles every time the server throttles up to max, and a throttled request is resent
+// at most retries times
+func (this *RpcClient) SetBackoff(min, max time.Duration, retries int) *RpcClient {
+	this.backoff = &backoff{min: min, max: max, retries: retries}
+	return this
+}
+
+// acquire wait for the rate limiter, the adaptive backoff and a free in-flight slot, release must be
+// called once the request is done
+func (this *RpcClient) acquire(ctx context.Context) (func(), error) {
+	wait := this.backoff.delay()
+	if this.limiter != nil {
+		if d := this.limiter.reserve(); d > wait {
+			wait = d
+		}
+	}
+	if err := sleep(ctx, wait); err != nil {
+		return nil, err
+	}
+	if this.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case this.inFlight <- struct{}{}:
+		return func() { <-this.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// throttled check whether the server refused the request because of load
+func throttled(resp *http.Response, body []byte) (*ThrottleErr, time.Duration) {
+	retryAfter := time.Duration(0)
+	if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil {
+		retryAfter = time.Duration(seconds) * time.Second
+	}
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusServiceUnavailable {
+		return &ThrottleErr{StatusCode: resp.StatusCode, Reason: http.StatusText(resp.StatusCode)}, retryAfter
+	}
+	if rpcErr := resultError(body); rpcErr == nil || rpcErr.Name != "slowDown" {
+		return nil, 0
+	}
+	return &ThrottleErr{StatusCode: resp.StatusCode, Reason: "slowDown"}, retryAfter
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,67 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"regexp"
+)
+
+// Logger is the structured logger of RpcClient, args are alternating keys and values. It is the method
+// set of *slog.Logger, which can be passed as is
+type Logger interface {
+	Debug(msg string, args ...interface{})
+	Info(msg string, args ...interface{})
+	Warn(msg string, args ...interface{})
+	Error(msg string, args ...interface{})
+}
+
+type nopLogger struct{}
+
+func (nopLogger) Debug(string, ...interface{}) {}
+func (nopLogger) Info(string, ...interface{})  {}
+func (nopLogger) Warn(string, ...interface{})  {}
+func (nopLogger) Error(string, ...interface{}) {}
+
+const Redacted = "[REDACTED]"
+
+var (
+	// json fields carrying secrets in rippled requests and responses, e.g. sign_for and wallet_propose
+	sensitiveField = regexp.MustCompile(`"(secret|seed|seed_hex|passphrase|master_seed|master_seed_hex|master_key|private_key)"(\s*:\s*)"[^"]*"`)
+	// base58 family seeds, secp256k1 seeds have 29 characters and ed25519 seeds 31
+	familySeed = regexp.MustCompile(`\bs[1-9A-HJ-NP-Za-km-z]{28,30}\b`)
+)
+
+// SetLogger set the logger of the client, nothing is logged by default
+func (this *RpcClient) SetLogger(logger Logger) *RpcClient {
+	if logger == nil {
+		logger = nopLogger{}
+	}
+	this.logger = logger
+	return this
+}
+
+// Redact hide the secrets and seeds in a request, response or message so that it can be logged
+func Redact(msg []byte) string {
+	return RedactString(string(msg))
+}
+
+// RedactString hide the secrets and seeds in s
+func RedactString(s string) string {
+	s = sensitiveField.ReplaceAllString(s, `"$1"$2"`+Redacted+`"`)
+	return familySeed.ReplaceAllString(s, Redacted)
+}
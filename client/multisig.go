@@ -0,0 +1,128 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// MultisigConfig is the signer list a multisig account is set up with
+type MultisigConfig struct {
+	Quorum        uint32
+	Signers       []types.SignerEntry
+	DisableMaster bool //DisableMaster leave the signer list as the only way to sign for the account
+}
+
+// MultisigSetup configure an account as a multisig account in steps: the SignerListSet and, if
+// configured, the AccountSet disabling the master key. Review each tx with Next before Submit signs
+// it with the master key and submits it
+type MultisigSetup struct {
+	Reserve *Reserve
+	rpc     *RpcClient
+	account *types.Account
+	steps   []data.Transaction
+	next    int
+}
+
+// NewMultisigSetup check account is funded for the signer list and its fees and prepare the steps
+// configuring it with config. The signer list takes one owner reserve. The steps take consecutive sequences
+// from the SequenceManager if one is set, so they follow the txs already in flight
+func (this *RpcClient) NewMultisigSetup(account *types.Account, config *MultisigConfig) (*MultisigSetup, error) {
+	address := account.Account.String()
+	if err := types.ValidateSignerList(account.Account, config.Quorum, config.Signers); err != nil {
+		return nil, fmt.Errorf("NewMultisigSetup: %s", err)
+	}
+	if config.Quorum == 0 {
+		return nil, fmt.Errorf("NewMultisigSetup: the signer list is empty")
+	}
+	reserve, err := this.GetReserve(address)
+	if err != nil {
+		return nil, fmt.Errorf("NewMultisigSetup: %s", err)
+	}
+	fee, err := this.GetTxFee(0)
+	if err != nil {
+		return nil, fmt.Errorf("NewMultisigSetup: %s", err)
+	}
+	feeDrops, err := valueDrops(fee)
+	if err != nil {
+		return nil, fmt.Errorf("NewMultisigSetup: %s", err)
+	}
+	count := uint32(1)
+	if config.DisableMaster {
+		count++
+	}
+	if required := reserve.RequiredFor(1) + feeDrops*uint64(count); reserve.Balance < required {
+		return nil, fmt.Errorf("NewMultisigSetup: account %s holds %d drops, %d drops are required", address, reserve.Balance, required)
+	}
+	sequence, err := this.nextSequences(account.Account, count)
+	if err != nil {
+		return nil, fmt.Errorf("NewMultisigSetup: %s", err)
+	}
+	signerListSet, err := types.GenerateSignerListSet(account.Account, config.Quorum, config.Signers, *fee, sequence)
+	if err != nil {
+		return nil, fmt.Errorf("NewMultisigSetup: %s", err)
+	}
+	steps := []data.Transaction{signerListSet}
+	if config.DisableMaster {
+		steps = append(steps, types.GenerateAccountSet(account.Account, types.AsfDisableMaster, *fee, sequence+1))
+	}
+	return &MultisigSetup{
+		Reserve: reserve,
+		rpc:     this,
+		account: account,
+		steps:   steps,
+	}, nil
+}
+
+// Steps return all the txs of the setup in submit order
+func (this *MultisigSetup) Steps() []data.Transaction {
+	return this.steps
+}
+
+// Next return the tx Submit submits next, nil once all are submitted
+func (this *MultisigSetup) Next() data.Transaction {
+	if this.next == len(this.steps) {
+		return nil
+	}
+	return this.steps[this.next]
+}
+
+// Submit sign the next tx with the master key and submit it, the setup moves to the following tx
+// only if the tx is accepted. The txs are final once validated, check them with GetTx
+func (this *MultisigSetup) Submit() (*SubmitRes, error) {
+	tx := this.Next()
+	if tx == nil {
+		return nil, fmt.Errorf("MultisigSetup.Submit: all txs are submitted")
+	}
+	txBlob, err := this.account.SignTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("MultisigSetup.Submit: %s", err)
+	}
+	res, err := this.rpc.SubmitTx(txBlob)
+	if err != nil {
+		return nil, fmt.Errorf("MultisigSetup.Submit: %s", err)
+	}
+	if !res.Accepted() {
+		return res, fmt.Errorf("MultisigSetup.Submit: %s rejected, engine result: %s, %s", tx.GetTransactionType(), res.Result.EngineResult, res.Result.EngineResultMessage)
+	}
+	this.next++
+	return res, nil
+}
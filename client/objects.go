@@ -0,0 +1,176 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"strconv"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// The object types account_objects filters on
+const (
+	AccountObjectCheck          = "check"
+	AccountObjectDepositPreauth = "deposit_preauth"
+	AccountObjectEscrow         = "escrow"
+	AccountObjectOffer          = "offer"
+	AccountObjectPaymentChannel = "payment_channel"
+	AccountObjectSignerList     = "signer_list"
+	AccountObjectState          = "state"
+	AccountObjectTicket         = "ticket"
+)
+
+// DropsPerXrp is the number of drops in one XRP
+const DropsPerXrp = types.DropsPerXrp
+
+// GetAccountObjects return one page of the ledger objects owned by account, objectType filters on one of
+// the AccountObject types unless empty. marker is empty for the first page and the returned marker is
+// empty after the last page, limit 0 lets the node choose the page size
+func (this *RpcClient) GetAccountObjects(account, objectType string, limit uint32, marker string, ledger ...LedgerSpecifier) (*AccountObjectsRes, error) {
+	accountObjectsReqParam := accountObjectsReqParam{
+		Account:         account,
+		Type:            objectType,
+		Limit:           limit,
+		Marker:          marker,
+		LedgerSpecifier: ledgerSpecifier(ledger),
+	}
+	respData, err := this.sendRpcRequest(RPC_ACCOUNT_OBJECTS, []interface{}{accountObjectsReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountObjects: send req err: %s", err)
+	}
+	result := &AccountObjectsRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountObjects: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetAccountObjects, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
+
+// GetAllAccountObjects return all the ledger objects of objectType owned by account, following the markers.
+// Pages after the first are read from the ledger of the first page, so that a moving ledger such as
+// validated gives a consistent result
+func (this *RpcClient) GetAllAccountObjects(account, objectType string, ledger ...LedgerSpecifier) (data.LedgerEntrySlice, error) {
+	spec := ledgerSpecifier(ledger)
+	var objects data.LedgerEntrySlice
+	marker := ""
+	for {
+		res, err := this.GetAccountObjects(account, objectType, 0, marker, spec)
+		if err != nil {
+			return nil, err
+		}
+		objects = append(objects, res.Result.AccountObjects...)
+		if res.Result.Marker == "" {
+			return objects, nil
+		}
+		if !spec.fixed() && res.Result.LedgerIndex != 0 {
+			spec = LedgerAtIndex(res.Result.LedgerIndex)
+		}
+		marker = res.Result.Marker
+	}
+}
+
+// Reserve is the XRP an account must hold, all amounts are in drops. An account holds the base reserve
+// plus the owner reserve for each object it owns, only the balance above is spendable
+type Reserve struct {
+	Base       uint64
+	Increment  uint64
+	OwnerCount uint32
+	Balance    uint64
+}
+
+// Required return the reserve of the account with the objects it owns
+func (this *Reserve) Required() uint64 {
+	return this.RequiredFor(0)
+}
+
+// RequiredFor return the reserve of the account after it creates objects more
+func (this *Reserve) RequiredFor(objects uint32) uint64 {
+	return this.Base + this.Increment*uint64(this.OwnerCount+objects)
+}
+
+// Spendable return the balance above the reserve, fees are paid from it as well
+func (this *Reserve) Spendable() uint64 {
+	if required := this.Required(); this.Balance > required {
+		return this.Balance - required
+	}
+	return 0
+}
+
+// CanAfford return whether the balance covers the reserve of objects more, the fee of the txs creating
+// them is not counted
+func (this *Reserve) CanAfford(objects uint32) bool {
+	return this.Balance >= this.RequiredFor(objects)
+}
+
+// GetReserve return the reserve of account, the reserves come from the last validated ledger of
+// server_info and the balance and owner count from account_info of ledger
+func (this *RpcClient) GetReserve(account string, ledger ...LedgerSpecifier) (*Reserve, error) {
+	info, err := this.GetServerInfo()
+	if err != nil {
+		return nil, fmt.Errorf("GetReserve: %s", err)
+	}
+	if info.ValidatedLedger == nil {
+		return nil, fmt.Errorf("GetReserve: no validated ledger, server state: %s", info.ServerState)
+	}
+	accountInfo, err := this.GetAccountInfo(account, ledger...)
+	if err != nil {
+		return nil, fmt.Errorf("GetReserve: %s", err)
+	}
+	root := accountInfo.AccountData
+	if root.Balance == nil || root.OwnerCount == nil {
+		return nil, fmt.Errorf("GetReserve: account %s not found", account)
+	}
+	balance, err := valueDrops(root.Balance)
+	if err != nil {
+		return nil, fmt.Errorf("GetReserve: %s", err)
+	}
+	return &Reserve{
+		Base:       xrpDrops(info.ValidatedLedger.ReserveBaseXrp),
+		Increment:  xrpDrops(info.ValidatedLedger.ReserveIncXrp),
+		OwnerCount: *root.OwnerCount,
+		Balance:    balance,
+	}, nil
+}
+
+// xrpDrops convert an amount of XRP as reported by server_info to drops
+func xrpDrops(xrp float64) uint64 {
+	return uint64(math.Round(xrp * DropsPerXrp))
+}
+
+// valueDrops return the drops of a native value, whose String is in XRP
+func valueDrops(value *data.Value) (uint64, error) {
+	if !value.IsNative() {
+		return 0, fmt.Errorf("%s is not an XRP amount", value)
+	}
+	text, err := value.MarshalText()
+	if err != nil {
+		return 0, err
+	}
+	drops, err := strconv.ParseUint(string(text), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid drops %s, err: %s", text, err)
+	}
+	return drops, nil
+}
@@ -0,0 +1,99 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// GetAccountOffers return one page of the offers placed by account. marker is empty for the first page
+// and the returned marker is empty after the last page, limit 0 lets the node choose the page size
+func (this *RpcClient) GetAccountOffers(account string, limit uint32, marker string, ledger ...LedgerSpecifier) (*AccountOffersRes, error) {
+	accountOffersReqParam := accountOffersReqParam{
+		Account:         account,
+		Limit:           limit,
+		Marker:          marker,
+		LedgerSpecifier: ledgerSpecifier(ledger),
+	}
+	respData, err := this.sendRpcRequest(RPC_ACCOUNT_OFFERS, []interface{}{accountOffersReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountOffers: send req err: %s", err)
+	}
+	result := &AccountOffersRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetAccountOffers: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetAccountOffers, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
+
+// GetAllAccountOffers return all the offers placed by account, following the markers on the ledger of
+// the first page as GetAllAccountObjects
+func (this *RpcClient) GetAllAccountOffers(account string, ledger ...LedgerSpecifier) ([]*AccountOffer, error) {
+	spec := ledgerSpecifier(ledger)
+	var offers []*AccountOffer
+	marker := ""
+	for {
+		res, err := this.GetAccountOffers(account, 0, marker, spec)
+		if err != nil {
+			return nil, err
+		}
+		offers = append(offers, res.Result.Offers...)
+		if res.Result.Marker == "" {
+			return offers, nil
+		}
+		if !spec.fixed() && res.Result.LedgerIndex != 0 {
+			spec = LedgerAtIndex(res.Result.LedgerIndex)
+		}
+		marker = res.Result.Marker
+	}
+}
+
+// GetBookOffers return one page of the order book of the offers giving takerGets for takerPays, best
+// quality first. taker is the account the funded amounts are computed for, empty for none. marker is
+// empty for the first page and the returned marker is empty after the last page, limit 0 lets the node
+// choose the page size
+func (this *RpcClient) GetBookOffers(takerGets, takerPays data.Asset, taker string, limit uint32, marker string, ledger ...LedgerSpecifier) (*BookOffersRes, error) {
+	bookOffersReqParam := bookOffersReqParam{
+		TakerGets:       takerGets,
+		TakerPays:       takerPays,
+		Taker:           taker,
+		Limit:           limit,
+		Marker:          marker,
+		LedgerSpecifier: ledgerSpecifier(ledger),
+	}
+	respData, err := this.sendRpcRequest(RPC_BOOK_OFFERS, []interface{}{bookOffersReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetBookOffers: send req err: %s", err)
+	}
+	result := &BookOffersRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetBookOffers: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetBookOffers, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
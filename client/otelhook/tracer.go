@@ -0,0 +1,81 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package otelhook traces the rpc requests of client.RpcClient with OpenTelemetry. It is a separate
+// module so that the sdk does not depend on OpenTelemetry unless this package is used.
+//
+// No sdk release has the client.Hook api yet, go.mod replaces the sdk by the tree this module lives in.
+// A replace directive only applies to the main module, so a project using otelhook must replace the
+// sdk by a checkout of this tree as well until the sdk is tagged.
+package otelhook
+
+import (
+	"context"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/polynetwork/ripple-sdk/client/otelhook"
+
+// Tracer is a client.Hook starting a client span for every rpc request
+type Tracer struct {
+	tracer trace.Tracer
+}
+
+// NewTracer return a tracer using provider, the global tracer provider if nil
+func NewTracer(provider trace.TracerProvider) *Tracer {
+	if provider == nil {
+		provider = otel.GetTracerProvider()
+	}
+	return &Tracer{tracer: provider.Tracer(instrumentationName)}
+}
+
+func (this *Tracer) Before(ctx context.Context, info *client.RequestInfo) context.Context {
+	ctx, _ = this.tracer.Start(ctx, "rippled "+info.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("rpc.system", "jsonrpc"),
+			attribute.String("rpc.method", info.Method),
+			attribute.String("url.full", info.Endpoint),
+			attribute.Int("rpc.request.size", info.RequestBytes),
+		))
+	return ctx
+}
+
+func (this *Tracer) After(ctx context.Context, info *client.RequestInfo) {
+	span := trace.SpanFromContext(ctx)
+	defer span.End()
+	span.SetAttributes(attribute.Int("rpc.response.size", info.ResponseBytes))
+	if info.ErrorClass == client.ErrorClassNone {
+		span.SetStatus(codes.Ok, "")
+		return
+	}
+	span.SetAttributes(attribute.String("error.type", info.ErrorClass))
+	if info.RpcError != "" {
+		span.SetAttributes(attribute.String("rpc.jsonrpc.error_message", info.RpcError))
+		span.SetStatus(codes.Error, info.RpcError)
+		return
+	}
+	if info.Err != nil {
+		span.RecordError(info.Err)
+		span.SetStatus(codes.Error, info.Err.Error())
+	}
+}
@@ -0,0 +1,53 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package otelhook
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client/clienttest"
+	"github.com/stretchr/testify/assert"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestTracer(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	c := s.Client().AddHook(NewTracer(provider))
+
+	_, err := c.GetCurrentHeight()
+	assert.Nil(t, err)
+	_, err = c.GetAccountInfo("rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF")
+	assert.Nil(t, err)
+
+	spans := recorder.Ended()
+	assert.Equal(t, 2, len(spans))
+	assert.Equal(t, "rippled ledger_closed", spans[0].Name())
+	assert.Equal(t, trace.SpanKindClient, spans[0].SpanKind())
+	assert.Equal(t, codes.Ok, spans[0].Status().Code)
+	assert.Contains(t, spans[0].Attributes(), attribute.String("rpc.method", "ledger_closed"))
+	assert.Equal(t, codes.Error, spans[1].Status().Code)
+	assert.Equal(t, "actNotFound", spans[1].Status().Description)
+	assert.Contains(t, spans[1].Attributes(), attribute.String("error.type", "rpc"))
+}
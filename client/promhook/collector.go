@@ -0,0 +1,103 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package promhook exports the rpc requests of client.RpcClient as Prometheus metrics. It is a separate
+// module so that the sdk does not depend on the Prometheus client unless this package is used.
+//
+// No sdk release has the client.Hook api yet, go.mod replaces the sdk by the tree this module lives in.
+// A replace directive only applies to the main module, so a project using promhook must replace the
+// sdk by a checkout of this tree as well until the sdk is tagged.
+package promhook
+
+import (
+	"context"
+
+	"github.com/polynetwork/ripple-sdk/client"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// Collector is a client.Hook and a prometheus.Collector, register it to both:
+//
+//	collector := promhook.NewCollector("ripple")
+//	prometheus.MustRegister(collector)
+//	rpcClient.AddHook(collector)
+type Collector struct {
+	requests      *prometheus.CounterVec
+	duration      *prometheus.HistogramVec
+	requestBytes  *prometheus.CounterVec
+	responseBytes *prometheus.CounterVec
+}
+
+// NewCollector return a collector whose metrics are prefixed by namespace
+func NewCollector(namespace string) *Collector {
+	return &Collector{
+		requests: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "requests_total",
+			Help:      "Number of rpc requests sent to rippled by method, endpoint and error class.",
+		}, []string{"method", "endpoint", "error_class"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "request_duration_seconds",
+			Help:      "Duration of the rpc requests sent to rippled, retries included.",
+			Buckets:   prometheus.DefBuckets,
+		}, []string{"method", "endpoint"}),
+		requestBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "request_bytes_total",
+			Help:      "Bytes of the rpc request bodies sent to rippled.",
+		}, []string{"method", "endpoint"}),
+		responseBytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "rpc",
+			Name:      "response_bytes_total",
+			Help:      "Bytes of the rpc response bodies received from rippled.",
+		}, []string{"method", "endpoint"}),
+	}
+}
+
+func (this *Collector) Before(ctx context.Context, info *client.RequestInfo) context.Context {
+	return ctx
+}
+
+func (this *Collector) After(ctx context.Context, info *client.RequestInfo) {
+	errorClass := info.ErrorClass
+	if errorClass == client.ErrorClassNone {
+		errorClass = "none"
+	}
+	this.requests.WithLabelValues(info.Method, info.Endpoint, errorClass).Inc()
+	this.duration.WithLabelValues(info.Method, info.Endpoint).Observe(info.Duration.Seconds())
+	this.requestBytes.WithLabelValues(info.Method, info.Endpoint).Add(float64(info.RequestBytes))
+	this.responseBytes.WithLabelValues(info.Method, info.Endpoint).Add(float64(info.ResponseBytes))
+}
+
+func (this *Collector) Describe(ch chan<- *prometheus.Desc) {
+	this.requests.Describe(ch)
+	this.duration.Describe(ch)
+	this.requestBytes.Describe(ch)
+	this.responseBytes.Describe(ch)
+}
+
+func (this *Collector) Collect(ch chan<- prometheus.Metric) {
+	this.requests.Collect(ch)
+	this.duration.Collect(ch)
+	this.requestBytes.Collect(ch)
+	this.responseBytes.Collect(ch)
+}
@@ -0,0 +1,48 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package promhook
+
+import (
+	"testing"
+
+	"github.com/polynetwork/ripple-sdk/client/clienttest"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCollector(t *testing.T) {
+	s := clienttest.NewServer()
+	defer s.Close()
+	collector := NewCollector("ripple")
+	registry := prometheus.NewRegistry()
+	assert.Nil(t, registry.Register(collector))
+	c := s.Client().AddHook(collector)
+
+	_, err := c.GetCurrentHeight()
+	assert.Nil(t, err)
+	_, err = c.GetAccountInfo("rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF")
+	assert.Nil(t, err)
+
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("ledger_closed", s.URL, "none")))
+	assert.Equal(t, 1.0, testutil.ToFloat64(collector.requests.WithLabelValues("account_info", s.URL, "rpc")))
+	assert.True(t, testutil.ToFloat64(collector.responseBytes.WithLabelValues("ledger_closed", s.URL)) > 0)
+	count, err := testutil.GatherAndCount(registry, "ripple_rpc_request_duration_seconds")
+	assert.Nil(t, err)
+	assert.Equal(t, 2, count)
+}
@@ -0,0 +1,263 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/hex"
+	"fmt"
+
+	"github.com/polynetwork/ripple-sdk/shamap"
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Item decode the blobs into a leaf of the transaction tree
+func (this *BinaryTx) Item() (*shamap.TransactionItem, error) {
+	txBlob, err := hex.DecodeString(this.TxBlob)
+	if err != nil {
+		return nil, fmt.Errorf("BinaryTx.Item: cannot decode tx blob, err: %s", err)
+	}
+	meta, err := hex.DecodeString(this.Meta)
+	if err != nil {
+		return nil, fmt.Errorf("BinaryTx.Item: cannot decode meta, err: %s", err)
+	}
+	return shamap.NewTransactionItem(txBlob, meta), nil
+}
+
+// Header decode the ledger header and check it against ledger_hash
+func (this *BinaryLedgerRes) Header() (*data.LedgerHeader, error) {
+	header, err := types.DeserializeLedgerHeader(this.Result.Ledger.LedgerData)
+	if err != nil {
+		return nil, err
+	}
+	ledger := &data.Ledger{LedgerHeader: *header}
+	hash, err := data.NewHash256(this.Result.LedgerHash)
+	if err != nil {
+		return nil, fmt.Errorf("BinaryLedgerRes.Header: invalid ledger hash %s, err: %s", this.Result.LedgerHash, err)
+	}
+	ledger.Hash = *hash
+	if err := types.VerifyLedgerHeader(ledger); err != nil {
+		return nil, err
+	}
+	return header, nil
+}
+
+// TransactionMap rebuild the transaction tree of the ledger and check its root against transaction_hash
+func (this *BinaryLedgerRes) TransactionMap() (*shamap.SHAMap, error) {
+	header, err := this.Header()
+	if err != nil {
+		return nil, fmt.Errorf("TransactionMap: %s", err)
+	}
+	items := make([]*shamap.TransactionItem, 0, len(this.Result.Ledger.Transactions))
+	for _, tx := range this.Result.Ledger.Transactions {
+		item, err := tx.Item()
+		if err != nil {
+			return nil, fmt.Errorf("TransactionMap: %s", err)
+		}
+		items = append(items, item)
+	}
+	m, err := shamap.NewTransactionMap(items)
+	if err != nil {
+		return nil, fmt.Errorf("TransactionMap: %s", err)
+	}
+	if m.Hash() != header.TransactionHash {
+		return nil, fmt.Errorf("TransactionMap: transaction hash mismatch, expect: %s, computed: %s",
+			header.TransactionHash.String(), m.Hash().String())
+	}
+	return m, nil
+}
+
+// GetTransactionProof build the inclusion proof of tx in the validated ledger of height,
+// the returned header is the one the proof is verified against
+func (this *RpcClient) GetTransactionProof(height uint32, txHash string) (*shamap.TransactionProof, *data.LedgerHeader, error) {
+	txId, err := data.NewHash256(txHash)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetTransactionProof: invalid tx hash %s, err: %s", txHash, err)
+	}
+	ledger, err := this.GetLedgerBinary(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetTransactionProof: %s", err)
+	}
+	if !ledger.Result.Validated {
+		return nil, nil, fmt.Errorf("GetTransactionProof: ledger %d is not validated", height)
+	}
+	header, err := ledger.Header()
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetTransactionProof: %s", err)
+	}
+	m, err := ledger.TransactionMap()
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetTransactionProof: %s", err)
+	}
+	proof, err := shamap.NewTransactionProof(m, *txId)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetTransactionProof: %s", err)
+	}
+	return proof, header, nil
+}
+
+// Item decode the entry into a leaf of the account state tree
+func (this *BinaryLedgerEntry) Item() (*shamap.StateItem, error) {
+	index, err := data.NewHash256(this.Index)
+	if err != nil {
+		return nil, fmt.Errorf("BinaryLedgerEntry.Item: invalid index %s, err: %s", this.Index, err)
+	}
+	blob, err := hex.DecodeString(this.Data)
+	if err != nil {
+		return nil, fmt.Errorf("BinaryLedgerEntry.Item: cannot decode data, err: %s", err)
+	}
+	return shamap.NewStateItem(*index, blob), nil
+}
+
+// Item decode the entry into a leaf of the account state tree
+func (this *LedgerEntryRes) Item() (*shamap.StateItem, error) {
+	entry := &BinaryLedgerEntry{Data: this.Result.NodeBinary, Index: this.Result.Index}
+	return entry.Item()
+}
+
+// StateSource pass every leaf of the account state of the ledger of height to visit, e.g. from a copy of
+// the state the caller keeps in sync. rippled has no proof rpc, a state proof needs the whole state tree
+type StateSource func(height uint32, visit func(item *shamap.StateItem) error) error
+
+// LedgerDataSource return the StateSource downloading the whole account state with ledger_data. That is
+// millions of entries on mainnet, use it against small or test ledgers only
+func (this *RpcClient) LedgerDataSource() StateSource {
+	return func(height uint32, visit func(item *shamap.StateItem) error) error {
+		marker := ""
+		for {
+			page, err := this.GetLedgerData(height, marker)
+			if err != nil {
+				return fmt.Errorf("LedgerDataSource: %s", err)
+			}
+			if !page.Result.Validated {
+				return fmt.Errorf("LedgerDataSource: ledger %d is not validated", height)
+			}
+			for _, entry := range page.Result.State {
+				item, err := entry.Item()
+				if err != nil {
+					return fmt.Errorf("LedgerDataSource: %s", err)
+				}
+				if err := visit(item); err != nil {
+					return fmt.Errorf("LedgerDataSource: %s", err)
+				}
+			}
+			if page.Result.Marker == "" {
+				return nil
+			}
+			marker = page.Result.Marker
+		}
+	}
+}
+
+// BuildStateMap build the account state tree of the validated ledger of height from the leaves of source
+// and check its root against account_hash
+func (this *RpcClient) BuildStateMap(height uint32, source StateSource) (*shamap.SHAMap, *data.LedgerHeader, error) {
+	ledger, err := this.GetLedgerBinary(height)
+	if err != nil {
+		return nil, nil, fmt.Errorf("BuildStateMap: %s", err)
+	}
+	if !ledger.Result.Validated {
+		return nil, nil, fmt.Errorf("BuildStateMap: ledger %d is not validated", height)
+	}
+	header, err := ledger.Header()
+	if err != nil {
+		return nil, nil, fmt.Errorf("BuildStateMap: %s", err)
+	}
+	m := shamap.NewSHAMap()
+	err = source(height, func(item *shamap.StateItem) error {
+		return m.Add(item)
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("BuildStateMap: %s", err)
+	}
+	if m.Hash() != header.StateHash {
+		return nil, nil, fmt.Errorf("BuildStateMap: account hash mismatch, expect: %s, computed: %s",
+			header.StateHash.String(), m.Hash().String())
+	}
+	return m, header, nil
+}
+
+// GetFullStateMap download the whole account state of the validated ledger of height with ledger_data,
+// see LedgerDataSource for its cost
+func (this *RpcClient) GetFullStateMap(height uint32) (*shamap.SHAMap, *data.LedgerHeader, error) {
+	return this.BuildStateMap(height, this.LedgerDataSource())
+}
+
+// GetStateProof build the proof of the ledger entry of index, e.g. an AccountRoot or SignerList, in the
+// validated ledger of height from the state of source. The returned header is the one the proof is
+// verified against
+func (this *RpcClient) GetStateProof(height uint32, index string, source StateSource) (*shamap.StateProof, *data.LedgerHeader, error) {
+	key, err := data.NewHash256(index)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetStateProof: invalid index %s, err: %s", index, err)
+	}
+	m, header, err := this.BuildStateMap(height, source)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetStateProof: %s", err)
+	}
+	proof, err := shamap.NewStateProof(m, *key)
+	if err != nil {
+		return nil, nil, fmt.Errorf("GetStateProof: %s", err)
+	}
+	return proof, header, nil
+}
+
+// Transaction decode the tx and its metadata
+func (this *BinaryTx) Transaction(ledger uint32) (*data.TransactionWithMetaData, error) {
+	return types.DeserializeTxWithMeta(this.TxBlob, this.Meta, ledger)
+}
+
+// Transaction decode the tx and its metadata
+func (this *BinaryTxRes) Transaction() (*data.TransactionWithMetaData, error) {
+	return types.DeserializeTxWithMeta(this.Result.Tx, this.Result.Meta, this.Result.LedgerIndex)
+}
+
+// checkHash check that the tx blob hashes to hash
+func (this *BinaryTxRes) checkHash(hash string) error {
+	expect, err := data.NewHash256(hash)
+	if err != nil {
+		return fmt.Errorf("BinaryTxRes: invalid tx hash %s, err: %s", hash, err)
+	}
+	txBlob, err := hex.DecodeString(this.Result.Tx)
+	if err != nil {
+		return fmt.Errorf("BinaryTxRes: cannot decode tx blob, err: %s", err)
+	}
+	if computed := types.TxHash(txBlob); computed != *expect {
+		return fmt.Errorf("BinaryTxRes: tx hash mismatch, expect: %s, computed: %s", expect.String(), computed.String())
+	}
+	return nil
+}
+
+// Item decode the blobs into a leaf of the transaction tree
+func (this *BinaryTxRes) Item() (*shamap.TransactionItem, error) {
+	tx := &BinaryTx{TxBlob: this.Result.Tx, Meta: this.Result.Meta}
+	return tx.Item()
+}
+
+// Transactions decode all the transactions of the ledger
+func (this *BinaryLedgerRes) Transactions() ([]*data.TransactionWithMetaData, error) {
+	txs := make([]*data.TransactionWithMetaData, 0, len(this.Result.Ledger.Transactions))
+	for _, tx := range this.Result.Ledger.Transactions {
+		txm, err := tx.Transaction(this.Result.LedgerIndex)
+		if err != nil {
+			return nil, err
+		}
+		txs = append(txs, txm)
+	}
+	return txs, nil
+}
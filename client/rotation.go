@@ -0,0 +1,224 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// GetSignerList return the signer list of account, nil if the account has none
+func (this *RpcClient) GetSignerList(account string, ledger ...LedgerSpecifier) (*types.SignerList, error) {
+	accountObjectsReqParam := accountObjectsReqParam{
+		Account:         account,
+		Type:            AccountObjectSignerList,
+		LedgerSpecifier: ledgerSpecifier(ledger),
+	}
+	respData, err := this.sendRpcRequest(RPC_ACCOUNT_OBJECTS, []interface{}{accountObjectsReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetSignerList: send req err: %s", err)
+	}
+	result := &SignerListRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetSignerList: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetSignerList, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	if len(result.Result.AccountObjects) == 0 {
+		return nil, nil
+	}
+	return result.Result.AccountObjects[0], nil
+}
+
+// SignerListDiff is the change from a signer list to another
+type SignerListDiff struct {
+	Added      []types.SignerEntry
+	Removed    []data.Account
+	Reweighted []types.SignerEntry //Reweighted are the signers kept with a new weight
+	OldQuorum  uint32
+	NewQuorum  uint32
+}
+
+// DiffSignerList return the change from current to the list of quorum and desired, current may be nil
+func DiffSignerList(current *types.SignerList, quorum uint32, desired []types.SignerEntry) *SignerListDiff {
+	diff := &SignerListDiff{NewQuorum: quorum}
+	if current == nil {
+		current = &types.SignerList{}
+	}
+	diff.OldQuorum = current.SignerQuorum
+	kept := make(map[data.Account]bool, len(desired))
+	for _, entry := range desired {
+		account := entry.SignerEntry.Account
+		kept[account] = true
+		switch weight := current.Weight(account); {
+		case weight == 0:
+			diff.Added = append(diff.Added, entry)
+		case weight != entry.SignerEntry.SignerWeight:
+			diff.Reweighted = append(diff.Reweighted, entry)
+		}
+	}
+	for _, entry := range current.SignerEntries {
+		if !kept[entry.SignerEntry.Account] {
+			diff.Removed = append(diff.Removed, entry.SignerEntry.Account)
+		}
+	}
+	sortEntries(diff.Added)
+	sortEntries(diff.Reweighted)
+	sort.Slice(diff.Removed, func(i, j int) bool {
+		return diff.Removed[i].Less(diff.Removed[j])
+	})
+	return diff
+}
+
+// Empty return whether the signer lists are the same
+func (this *SignerListDiff) Empty() bool {
+	return len(this.Added) == 0 && len(this.Removed) == 0 && len(this.Reweighted) == 0 && this.OldQuorum == this.NewQuorum
+}
+
+func sortEntries(entries []types.SignerEntry) {
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].SignerEntry.Account.Less(entries[j].SignerEntry.Account)
+	})
+}
+
+// SignerRotation replace the signer list of a multisig account, the SignerListSet is multi-signed by
+// the signers of the current list. Pass RawTx to each old signer's Account.MultiSignTransaction and
+// collect the results with AddSigned, then Submit once the current quorum is reached
+type SignerRotation struct {
+	Diff    *SignerListDiff
+	Tx      *types.SignerListSet
+	rpc     *RpcClient
+	current *types.SignerList
+	rawTx   string
+	signers map[data.Account]data.Signer
+}
+
+// NewSignerRotation prepare the rotation of the signer list of account to quorum and desired, diffing
+// against the validated signer list. The fee is paid for every current signer to sign
+func (this *RpcClient) NewSignerRotation(account string, quorum uint32, desired []types.SignerEntry) (*SignerRotation, error) {
+	owner, err := data.NewAccountFromAddress(account)
+	if err != nil {
+		return nil, fmt.Errorf("NewSignerRotation: invalid account %s, err: %s", account, err)
+	}
+	current, err := this.GetSignerList(account, LedgerValidated)
+	if err != nil {
+		return nil, fmt.Errorf("NewSignerRotation: %s", err)
+	}
+	if current == nil {
+		return nil, fmt.Errorf("NewSignerRotation: account %s has no signer list", account)
+	}
+	diff := DiffSignerList(current, quorum, desired)
+	if diff.Empty() {
+		return nil, fmt.Errorf("NewSignerRotation: signer list of %s is up to date", account)
+	}
+	tx, err := types.GenerateSignerListSet(*owner, quorum, desired, data.Value{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("NewSignerRotation: %s", err)
+	}
+	if err := this.Autofill(tx, len(current.SignerEntries)); err != nil {
+		return nil, fmt.Errorf("NewSignerRotation: %s", err)
+	}
+	rawTx, err := types.SerializeRawMultiSignTransaction(tx)
+	if err != nil {
+		return nil, fmt.Errorf("NewSignerRotation: %s", err)
+	}
+	return &SignerRotation{
+		Diff:    diff,
+		Tx:      tx,
+		rpc:     this,
+		current: current,
+		rawTx:   rawTx,
+		signers: make(map[data.Account]data.Signer),
+	}, nil
+}
+
+// RawTx return the unsigned SignerListSet the old signers sign
+func (this *SignerRotation) RawTx() string {
+	return this.rawTx
+}
+
+// AddSignature add the signature of signer after checking it is a current signer and its signature
+// is valid
+func (this *SignerRotation) AddSignature(signer data.Signer) error {
+	account := signer.Signer.Account
+	if this.current.Weight(account) == 0 {
+		return fmt.Errorf("AddSignature: %s is not a signer of the current list", account)
+	}
+	if signer.Signer.SigningPubKey == nil || signer.Signer.TxnSignature == nil {
+		return fmt.Errorf("AddSignature: signature of %s is incomplete", account)
+	}
+	if err := types.CheckMultiSign(this.rawTx, account, signer.Signer.SigningPubKey.Bytes(), *signer.Signer.TxnSignature); err != nil {
+		return fmt.Errorf("AddSignature: signer %s, err: %s", account, err)
+	}
+	this.signers[account] = signer
+	return nil
+}
+
+// AddSigned add the signatures of a tx returned by MultiSignTransaction
+func (this *SignerRotation) AddSigned(tx data.Transaction) error {
+	for _, signer := range tx.GetBase().Signers {
+		if err := this.AddSignature(signer); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Weight return the total weight of the signatures collected
+func (this *SignerRotation) Weight() uint32 {
+	var weight uint32
+	for account := range this.signers {
+		weight += uint32(this.current.Weight(account))
+	}
+	return weight
+}
+
+// QuorumReached return whether the signatures reach the quorum of the current list
+func (this *SignerRotation) QuorumReached() bool {
+	return this.Weight() >= this.current.SignerQuorum
+}
+
+// Submit submit the SignerListSet with the signatures collected, it is final once validated
+func (this *SignerRotation) Submit() (*SubmitMultisignRes, error) {
+	if !this.QuorumReached() {
+		return nil, fmt.Errorf("SignerRotation.Submit: weight %d is below quorum %d", this.Weight(), this.current.SignerQuorum)
+	}
+	tx, err := types.DeserializeRawMultiSignTransaction(this.rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("SignerRotation.Submit: %s", err)
+	}
+	signers := make([]data.Signer, 0, len(this.signers))
+	for _, signer := range this.signers {
+		signers = append(signers, signer)
+	}
+	types.AddSigners(tx, signers...)
+	res, err := this.rpc.SubmitMultisignedTx(tx)
+	if err != nil {
+		return nil, fmt.Errorf("SignerRotation.Submit: %s", err)
+	}
+	if res.Result.EngineResult != "tesSUCCESS" && res.Result.EngineResult != "terQUEUED" {
+		return res, fmt.Errorf("SignerRotation.Submit: rejected, engine result: %s, %s", res.Result.EngineResult, res.Result.EngineResultMessage)
+	}
+	return res, nil
+}
@@ -19,6 +19,7 @@ package client
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/polynetwork/ripple-sdk/types"
@@ -26,13 +27,25 @@ import (
 	"net/http"
 	"time"
 
+	"github.com/rubblelabs/ripple/data"
 	"github.com/rubblelabs/ripple/websockets"
 )
 
 //RpcClient for ontology rpc api
 type RpcClient struct {
-	addr       string
-	httpClient *http.Client
+	addr             string
+	httpClient       *http.Client
+	retries          int
+	retryDelay       time.Duration
+	batchParallelism int
+	batchUnsupported int32
+	limiter          *tokenBucket
+	inFlight         chan struct{}
+	backoff          *backoff
+	hooks            []Hook
+	logger           Logger
+	cache            Cache
+	sequences        *SequenceManager
 }
 
 //NewRpcClient return RpcClient instance
@@ -47,9 +60,19 @@ func NewRpcClient() *RpcClient {
 			},
 			Timeout: time.Second * 300, //timeout for http response
 		},
+		batchParallelism: DefaultBatchParallelism,
+		backoff:          &backoff{min: DefaultBackoffMin, max: DefaultBackoffMax, retries: DefaultBackoffRetries},
+		logger:           nopLogger{},
 	}
 }
 
+//SetRetries set how many times a request is resent after a transport failure, waiting delay between attempts
+func (this *RpcClient) SetRetries(retries int, delay time.Duration) *RpcClient {
+	this.retries = retries
+	this.retryDelay = delay
+	return this
+}
+
 //SetAddress set rpc server address. Simple http://localhost:20336
 func (this *RpcClient) SetAddress(addr string) *RpcClient {
 	this.addr = addr
@@ -70,7 +93,7 @@ func (this *RpcClient) GetCurrentHeight() (uint32, error) {
 	result := &heightResp{}
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		return 0, fmt.Errorf("GetCurrentHeight: unmarshal resp err: %s, origin resp is %s", err, string(respData))
+		return 0, fmt.Errorf("GetCurrentHeight: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
 	}
 	if result.Result.Status != "success" {
 		return 0, fmt.Errorf("GetCurrentHeight, resp failed, status: %s", result.Result.Status)
@@ -78,24 +101,180 @@ func (this *RpcClient) GetCurrentHeight() (uint32, error) {
 	return result.Result.LedgerIndex, nil
 }
 
-func (this *RpcClient) GetLedger(height uint32) (*websockets.LedgerResult, error) {
+//GetValidatedHeight return the index of the latest validated ledger, unlike GetCurrentHeight the ledger is final
+func (this *RpcClient) GetValidatedHeight() (uint32, error) {
 	ledgerReqParam := ledgerReqParam{
-		LedgerIndex:  height,
-		Transactions: true,
-		Expand:       true,
+		LedgerSpecifier: LedgerValidated,
 	}
 	respData, err := this.sendRpcRequest(RPC_LEDGER, []interface{}{ledgerReqParam})
+	if err != nil {
+		return 0, fmt.Errorf("GetValidatedHeight: send req err: %s", err)
+	}
+	result := &heightResp{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return 0, fmt.Errorf("GetValidatedHeight: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return 0, fmt.Errorf("GetValidatedHeight, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	if !result.Result.Validated {
+		return 0, fmt.Errorf("GetValidatedHeight, ledger %d is not validated", result.Result.LedgerIndex)
+	}
+	return result.Result.LedgerIndex, nil
+}
+
+func (this *RpcClient) GetLedger(height uint32) (*websockets.LedgerResult, error) {
+	return this.GetLedgerAt(LedgerAtIndex(height))
+}
+
+//GetLedgerAt return the ledger header and transactions of the ledger selected by ledger
+func (this *RpcClient) GetLedgerAt(ledger LedgerSpecifier) (*websockets.LedgerResult, error) {
+	ledgerReqParam := ledgerReqParam{
+		LedgerSpecifier: ledger,
+		Transactions:    true,
+		Expand:          true,
+	}
+	key := ledgerCacheKey(&ledgerReqParam)
+	respData, err := this.sendCachedRequest(key, RPC_LEDGER, []interface{}{ledgerReqParam}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("GetLedger: send req err: %s", err)
 	}
 	result := &websockets.LedgerCommand{}
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		return nil, fmt.Errorf("GetLedger: unmarshal resp err: %s, origin resp is %s", err, string(respData))
+		return nil, fmt.Errorf("GetLedger: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if rpcErr := resultError(respData); rpcErr != nil {
+		return nil, fmt.Errorf("GetLedger, resp failed, status: error, error: %s", rpcErr)
+	}
+	if result.Result == nil {
+		return nil, fmt.Errorf("GetLedger, resp failed, origin resp is %s", Redact(respData))
 	}
 	return result.Result, nil
 }
 
+//GetLedgerBinary return the ledger header and transactions of height in binary format
+func (this *RpcClient) GetLedgerBinary(height uint32) (*BinaryLedgerRes, error) {
+	return this.GetLedgerBinaryAt(LedgerAtIndex(height))
+}
+
+//GetLedgerBinaryAt return the ledger header and transactions of the ledger selected by ledger in binary format
+func (this *RpcClient) GetLedgerBinaryAt(ledger LedgerSpecifier) (*BinaryLedgerRes, error) {
+	ledgerReqParam := ledgerReqParam{
+		LedgerSpecifier: ledger,
+		Transactions:    true,
+		Expand:          true,
+		Binary:          true,
+	}
+	key := ledgerCacheKey(&ledgerReqParam)
+	respData, err := this.sendCachedRequest(key, RPC_LEDGER, []interface{}{ledgerReqParam}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("GetLedgerBinary: send req err: %s", err)
+	}
+	result := &BinaryLedgerRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetLedgerBinary: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetLedgerBinary, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
+
+//GetLedgerEntry return the ledger entry of index in the ledger of height in binary format
+func (this *RpcClient) GetLedgerEntry(index string, height uint32) (*LedgerEntryRes, error) {
+	return this.GetLedgerEntryAt(index, LedgerAtIndex(height))
+}
+
+//GetLedgerEntryAt return the ledger entry of index in the ledger selected by ledger in binary format
+func (this *RpcClient) GetLedgerEntryAt(index string, ledger LedgerSpecifier) (*LedgerEntryRes, error) {
+	ledgerEntryReqParam := ledgerEntryReqParam{
+		Index:           index,
+		LedgerSpecifier: ledger,
+		Binary:          true,
+	}
+	respData, err := this.sendRpcRequest(RPC_LEDGER_ENTRY, []interface{}{ledgerEntryReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetLedgerEntry: send req err: %s", err)
+	}
+	result := &LedgerEntryRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetLedgerEntry: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetLedgerEntry, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
+
+//GetLedgerData return one page of the account state of the ledger of height in binary format,
+//marker is empty for the first page and the returned marker is empty after the last page
+func (this *RpcClient) GetLedgerData(height uint32, marker string) (*LedgerDataRes, error) {
+	return this.GetLedgerDataAt(LedgerAtIndex(height), marker)
+}
+
+//GetLedgerDataAt return one page of the account state of the ledger selected by ledger in binary format.
+//Select the ledger by index or hash, validated or closed may move to a newer ledger between pages
+func (this *RpcClient) GetLedgerDataAt(ledger LedgerSpecifier, marker string) (*LedgerDataRes, error) {
+	ledgerDataReqParam := ledgerDataReqParam{
+		LedgerSpecifier: ledger,
+		Binary:          true,
+		Marker:          marker,
+	}
+	respData, err := this.sendRpcRequest(RPC_LEDGER_DATA, []interface{}{ledgerDataReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetLedgerData: send req err: %s", err)
+	}
+	result := &LedgerDataRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetLedgerData: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetLedgerData, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result, nil
+}
+
+//GetManifest return the latest manifest the node knows of the validator with master or ephemeral key publicKey
+func (this *RpcClient) GetManifest(publicKey string) (*types.Manifest, error) {
+	manifestReqParam := manifestReqParam{
+		PublicKey: publicKey,
+	}
+	respData, err := this.sendRpcRequest(RPC_MANIFEST, []interface{}{manifestReqParam})
+	if err != nil {
+		return nil, fmt.Errorf("GetManifest: send req err: %s", err)
+	}
+	result := &ManifestRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetManifest: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" || result.Result.Manifest == "" {
+		return nil, fmt.Errorf("GetManifest, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	manifest, err := types.ParseManifest(result.Result.Manifest)
+	if err != nil {
+		return nil, fmt.Errorf("GetManifest: %s", err)
+	}
+	return manifest, nil
+}
+
+//GetVerifiedLedger return the ledger of height after checking its hash matches the header fields
+func (this *RpcClient) GetVerifiedLedger(height uint32) (*websockets.LedgerResult, error) {
+	result, err := this.GetLedger(height)
+	if err != nil {
+		return nil, fmt.Errorf("GetVerifiedLedger: %s", err)
+	}
+	if err := types.VerifyLedgerHeader(&result.Ledger); err != nil {
+		return nil, fmt.Errorf("GetVerifiedLedger: %s", err)
+	}
+	return result, nil
+}
+
 //SignFor sign method for multi-sign account
 func (this *RpcClient) SignFor(account, secret string, txJson *types.MultisignPayment) (*SignRes, error) {
 	sigForReqParam := sigForReqParam{
@@ -110,7 +289,7 @@ func (this *RpcClient) SignFor(account, secret string, txJson *types.MultisignPa
 	result := &SignRes{}
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		return nil, fmt.Errorf("SignFor: unmarshal resp err: %s, origin resp is %s", err, string(respData))
+		return nil, fmt.Errorf("SignFor: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
 	}
 	return result, nil
 }
@@ -131,11 +310,74 @@ func (this *RpcClient) SubmitMultisigned(txJson *types.MultisignPayment) (*Submi
 	return submitRes, nil
 }
 
-func (this *RpcClient) GetAccountInfo(account string) (*websockets.AccountInfoResult, error) {
+//SubmitMultisignedTx submit a multi-signed tx of any type, e.g. a SignerListSet
+func (this *RpcClient) SubmitMultisignedTx(tx data.Transaction) (*SubmitMultisignRes, error) {
+	txJson, err := toTxJson(tx)
+	if err != nil {
+		return nil, fmt.Errorf("SubmitMultisignedTx: %s", err)
+	}
+	submitMultisignedTxJsonReq := submitMultisignedTxJsonReq{
+		TxJson: txJson,
+	}
+	respData, err := this.sendRpcRequest(RPC_SUBMIT_MULTISIGNED, []interface{}{submitMultisignedTxJsonReq})
+	if err != nil {
+		return nil, fmt.Errorf("SubmitMultisignedTx: send req err: %s", err)
+	}
+	submitRes := &SubmitMultisignRes{}
+	err = json.Unmarshal(respData, submitRes)
+	if err != nil {
+		return nil, fmt.Errorf("SubmitMultisignedTx: unmarshal submit tx resp err: %s", err)
+	}
+	if submitRes.Result.Status != "success" {
+		return nil, fmt.Errorf("SubmitMultisignedTx, resp failed, status: %s, error: %s", submitRes.Result.Status, submitRes.Result.ErrorMessage)
+	}
+	return submitRes, nil
+}
+
+//toTxJson return the tx_json of tx, without the hash the library adds
+func toTxJson(tx data.Transaction) (map[string]interface{}, error) {
+	raw, err := json.Marshal(tx)
+	if err != nil {
+		return nil, fmt.Errorf("marshal tx err: %s", err)
+	}
+	txJson := make(map[string]interface{})
+	if err := json.Unmarshal(raw, &txJson); err != nil {
+		return nil, fmt.Errorf("unmarshal tx err: %s", err)
+	}
+	delete(txJson, "hash")
+	return txJson, nil
+}
+
+//SubmitTx submit a signed tx blob, resyncing the SequenceManager if the sequence was not consumed
+func (this *RpcClient) SubmitTx(txBlob string) (*SubmitRes, error) {
+	submitTxReq := submitTxReq{
+		TxBlob: txBlob,
+	}
+	respData, err := this.sendRpcRequest(RPC_SUBMIT, []interface{}{submitTxReq})
+	if err != nil {
+		return nil, fmt.Errorf("SubmitTx: send req err: %s", err)
+	}
+	submitRes := &SubmitRes{}
+	err = json.Unmarshal(respData, submitRes)
+	if err != nil {
+		return nil, fmt.Errorf("SubmitTx: unmarshal submit tx resp err: %s", err)
+	}
+	if submitRes.Result.Status != "success" {
+		return nil, fmt.Errorf("SubmitTx, resp failed, status: %s, error: %s", submitRes.Result.Status, submitRes.Result.ErrorMessage)
+	}
+	if this.sequences != nil {
+		this.sequences.observe(txBlob, submitRes.Result.EngineResult)
+	}
+	return submitRes, nil
+}
+
+//GetAccountInfo return the account root of account, in the current ledger unless ledger select another one
+func (this *RpcClient) GetAccountInfo(account string, ledger ...LedgerSpecifier) (*websockets.AccountInfoResult, error) {
 	accountReqParam := accountInfoReqParam{
-		Account: account,
-		Strict:  true,
-		Queue:   false,
+		Account:         account,
+		Strict:          true,
+		Queue:           false,
+		LedgerSpecifier: ledgerSpecifier(ledger),
 	}
 	respData, err := this.sendRpcRequest(RPC_ACCOUNT_INFO, []interface{}{accountReqParam})
 	if err != nil {
@@ -144,7 +386,7 @@ func (this *RpcClient) GetAccountInfo(account string) (*websockets.AccountInfoRe
 	result := &websockets.AccountInfoCommand{}
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		return nil, fmt.Errorf("GetAccountInfo: unmarshal resp err: %s, origin resp is %s", err, string(respData))
+		return nil, fmt.Errorf("GetAccountInfo: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
 	}
 	return result.Result, nil
 }
@@ -157,31 +399,124 @@ func (this *RpcClient) GetFee() (*websockets.FeeResult, error) {
 	result := &websockets.FeeCommand{}
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		return nil, fmt.Errorf("GetFee: unmarshal resp err: %s, origin resp is %s", err, string(respData))
+		return nil, fmt.Errorf("GetFee: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
 	}
 	return result.Result, nil
 }
 
-//Tx return the tx info of hash
-func (this *RpcClient) GetTx(hash string) (*websockets.TxResult, error) {
+//Tx return the tx info of hash. If ledger is given, the tx must be in the selected ledger: validated
+//requires the tx to be validated, closed requires it to be in a closed ledger and a ledger hash requires
+//it to be in that ledger once validated
+func (this *RpcClient) GetTx(hash string, ledger ...LedgerSpecifier) (*websockets.TxResult, error) {
+	check, err := this.txLedger(ledger)
+	if err != nil {
+		return nil, fmt.Errorf("GetTx: %s, hash: %s", err, hash)
+	}
 	txReqParam := txReqParam{
 		Transaction: hash,
 		Binary:      false,
 	}
-	respData, err := this.sendRpcRequest(RPC_TX, []interface{}{txReqParam})
+	respData, err := this.sendCachedRequest(txCacheKey(hash, false), RPC_TX, []interface{}{txReqParam}, nil)
 	if err != nil {
 		return nil, fmt.Errorf("GetTx: send req err: %s", err)
 	}
 	result := &websockets.TxCommand{}
 	err = json.Unmarshal(respData, result)
 	if err != nil {
-		return nil, fmt.Errorf("GetTx: unmarshal resp err: %s, origin resp is %s", err, string(respData))
+		return nil, fmt.Errorf("GetTx: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result == nil {
+		return nil, fmt.Errorf("GetTx, resp failed, origin resp is %s", Redact(respData))
+	}
+	if err := check(result.Result.Validated, result.Result.LedgerSequence); err != nil {
+		return nil, fmt.Errorf("GetTx: %s, hash: %s", err, hash)
 	}
 	return result.Result, nil
 }
 
+//GetTxBinary return the tx blob and metadata blob of hash, exactly as the tx was applied. ledger is
+//checked as in GetTx, and the blob must hash to hash
+func (this *RpcClient) GetTxBinary(hash string, ledger ...LedgerSpecifier) (*BinaryTxRes, error) {
+	check, err := this.txLedger(ledger)
+	if err != nil {
+		return nil, fmt.Errorf("GetTxBinary: %s, hash: %s", err, hash)
+	}
+	txReqParam := txReqParam{
+		Transaction: hash,
+		Binary:      true,
+	}
+	verify := func(respData []byte) error {
+		result := &BinaryTxRes{}
+		if err := json.Unmarshal(respData, result); err != nil || result.Result.Status != "success" {
+			return nil
+		}
+		return result.checkHash(hash)
+	}
+	respData, err := this.sendCachedRequest(txCacheKey(hash, true), RPC_TX, []interface{}{txReqParam}, verify)
+	if err != nil {
+		return nil, fmt.Errorf("GetTxBinary: send req err: %s", err)
+	}
+	result := &BinaryTxRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetTxBinary: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("GetTxBinary, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	if err := check(result.Result.Validated, result.Result.LedgerIndex); err != nil {
+		return nil, fmt.Errorf("GetTxBinary: %s, hash: %s", err, hash)
+	}
+	return result, nil
+}
+
+//Call send method with params to ripple and decode the result object into result, a failed request is
+//returned as *RpcError. It reaches any rippled api the sdk has no typed wrapper for
+func (this *RpcClient) Call(ctx context.Context, method string, params interface{}, result interface{}) error {
+	reqParams := []interface{}{}
+	if params != nil {
+		reqParams = append(reqParams, params)
+	}
+	respData, err := this.sendRpcRequestWithContext(ctx, method, reqParams)
+	if err != nil {
+		return fmt.Errorf("Call: send %s req err: %w", method, err)
+	}
+	resp := &rpcResp{}
+	err = json.Unmarshal(respData, resp)
+	if err != nil || len(resp.Result) == 0 {
+		return fmt.Errorf("Call: unmarshal %s resp err: %v, origin resp is %s", method, err, Redact(respData))
+	}
+	return decodeResult(method, resp.Result, result)
+}
+
+//decodeResult decode the result object of method into result, or return the *RpcError it carries
+func decodeResult(method string, raw json.RawMessage, result interface{}) error {
+	status := &rpcStatus{}
+	err := json.Unmarshal(raw, status)
+	if err != nil {
+		return fmt.Errorf("decodeResult: unmarshal %s result err: %s, origin result is %s", method, err, Redact(raw))
+	}
+	if status.Status == "error" || status.Name != "" {
+		rpcErr := status.RpcError
+		return &rpcErr
+	}
+	if result == nil {
+		return nil
+	}
+	err = json.Unmarshal(raw, result)
+	if err != nil {
+		return fmt.Errorf("decodeResult: unmarshal %s result err: %s, origin result is %s", method, err, Redact(raw))
+	}
+	return nil
+}
+
 //sendRpcRequest send Rpc request to ripple
 func (this *RpcClient) sendRpcRequest(method string, params []interface{}) ([]byte, error) {
+	return this.sendRpcRequestWithContext(context.Background(), method, params)
+}
+
+//sendRpcRequestWithContext send Rpc request to ripple, the hooks observe it as a whole
+func (this *RpcClient) sendRpcRequestWithContext(ctx context.Context, method string, params []interface{}) ([]byte, error) {
 	rpcReq := &JsonRpcRequest{
 		Method: method,
 		Params: params,
@@ -190,9 +525,64 @@ func (this *RpcClient) sendRpcRequest(method string, params []interface{}) ([]by
 	if err != nil {
 		return nil, fmt.Errorf("JsonRpcRequest json.Marsha error:%s", err)
 	}
-	resp, err := this.httpClient.Post(this.addr, "application/json", bytes.NewReader(data))
+	if len(this.hooks) == 0 {
+		return this.send(ctx, method, data)
+	}
+	return this.sendWithHooks(ctx, method, data)
+}
+
+//send post data, it is resent on transport failure and, after backing off, when the server throttles it
+func (this *RpcClient) send(ctx context.Context, method string, data []byte) ([]byte, error) {
+	this.logger.Debug("rpc request", "method", method, "endpoint", this.addr, "request", Redact(data))
+	start := time.Now()
+	failures, throttles := 0, 0
+	for {
+		body, err := this.post(ctx, data)
+		switch e := err.(type) {
+		case nil:
+			this.logger.Debug("rpc response", "method", method, "endpoint", this.addr,
+				"duration", time.Since(start), "bytes", len(body))
+			return body, nil
+		case PostErr:
+			if failures >= this.retries || ctx.Err() != nil {
+				this.logger.Error("rpc request failed", "method", method, "endpoint", this.addr, "error", err)
+				return nil, err
+			}
+			failures++
+			this.logger.Warn("rpc request failed, retrying", "method", method, "endpoint", this.addr,
+				"attempt", failures, "error", err)
+			if err := sleep(ctx, this.retryDelay); err != nil {
+				return nil, err
+			}
+		case ThrottleErr:
+			if throttles >= this.backoff.retries || ctx.Err() != nil {
+				this.logger.Error("rpc request throttled", "method", method, "endpoint", this.addr, "error", err)
+				return body, err
+			}
+			throttles++
+			this.logger.Warn("rpc request throttled, backing off", "method", method, "endpoint", this.addr,
+				"reason", e.Reason, "delay", this.backoff.delay())
+		default:
+			this.logger.Error("rpc request failed", "method", method, "endpoint", this.addr, "error", err)
+			return body, err
+		}
+	}
+}
+
+func (this *RpcClient) post(ctx context.Context, data []byte) ([]byte, error) {
+	release, err := this.acquire(ctx)
 	if err != nil {
-		return nil, PostErr{fmt.Errorf("http post request:%s error:%s", data, err)}
+		return nil, PostErr{fmt.Errorf("wait to send request:%s error:%s", Redact(data), err)}
+	}
+	defer release()
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, this.addr, bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("new http request error:%s", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	resp, err := this.httpClient.Do(req)
+	if err != nil {
+		return nil, PostErr{fmt.Errorf("http post request:%s error:%s", Redact(data), RedactString(err.Error()))}
 	}
 	defer resp.Body.Close()
 
@@ -200,6 +590,11 @@ func (this *RpcClient) sendRpcRequest(method string, params []interface{}) ([]by
 	if err != nil {
 		return nil, fmt.Errorf("read rpc response body error:%s", err)
 	}
+	if throttleErr, retryAfter := throttled(resp, body); throttleErr != nil {
+		this.backoff.throttled(retryAfter)
+		return body, *throttleErr
+	}
+	this.backoff.succeeded()
 	return body, nil
 }
 
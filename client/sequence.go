@@ -0,0 +1,150 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// SequenceManager allocate the sequences of single-signed txs locally, so that a burst of txs from one
+// account gets consecutive sequences instead of all reading the same one from account_info. An account
+// is seeded from account_info on first use, and reseeded after a submit shows the local sequence is out
+// of step with the ledger. It is safe for concurrent use
+type SequenceManager struct {
+	lock     sync.Mutex
+	accounts map[data.Account]*accountSequence
+}
+
+type accountSequence struct {
+	lock   sync.Mutex //lock is held while seeding, so that concurrent first uses read account_info once
+	next   uint32
+	seeded bool
+}
+
+// NewSequenceManager return an empty SequenceManager, set it on the client with SetSequenceManager
+func NewSequenceManager() *SequenceManager {
+	return &SequenceManager{accounts: make(map[data.Account]*accountSequence)}
+}
+
+// SetSequenceManager allocate the sequences Autofill sets with manager, and let SubmitTx resync it.
+// nil reads every sequence from account_info
+func (this *RpcClient) SetSequenceManager(manager *SequenceManager) *RpcClient {
+	this.sequences = manager
+	return this
+}
+
+// NextSequence return the sequence of the next tx of account, allocated by the SequenceManager if one
+// is set, else read from account_info
+func (this *RpcClient) NextSequence(account data.Account) (uint32, error) {
+	return this.nextSequences(account, 1)
+}
+
+// nextSequences return the first of count consecutive sequences of account, allocated at once so that
+// concurrent allocations do not interleave with them
+func (this *RpcClient) nextSequences(account data.Account, count uint32) (uint32, error) {
+	if this.sequences != nil {
+		return this.sequences.next(this, account, count)
+	}
+	return this.accountSequence(account)
+}
+
+func (this *RpcClient) accountSequence(account data.Account) (uint32, error) {
+	info, err := this.GetAccountInfo(account.String())
+	if err != nil {
+		return 0, fmt.Errorf("NextSequence: %s", err)
+	}
+	if info.AccountData.Sequence == nil {
+		return 0, fmt.Errorf("NextSequence: account %s not found", account)
+	}
+	return *info.AccountData.Sequence, nil
+}
+
+func (this *SequenceManager) account(account data.Account) *accountSequence {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	state, ok := this.accounts[account]
+	if !ok {
+		state = &accountSequence{}
+		this.accounts[account] = state
+	}
+	return state
+}
+
+func (this *SequenceManager) next(rpc *RpcClient, account data.Account, count uint32) (uint32, error) {
+	state := this.account(account)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	if !state.seeded {
+		sequence, err := rpc.accountSequence(account)
+		if err != nil {
+			return 0, err
+		}
+		state.next, state.seeded = sequence, true
+	}
+	sequence := state.next
+	state.next += count
+	return sequence, nil
+}
+
+// Reset forget the sequence of account, the next one is read from account_info. Reset after abandoning
+// a tx whose sequence was allocated, since the later sequences can't apply until the gap is filled
+func (this *SequenceManager) Reset(account data.Account) {
+	state := this.account(account)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	state.seeded = false
+}
+
+// release give back sequence if it is the last one allocated for account, a lower one is left to the
+// caller since the sequences above it are held by txs in flight
+func (this *SequenceManager) release(account data.Account, sequence uint32) {
+	state := this.account(account)
+	state.lock.Lock()
+	defer state.lock.Unlock()
+	if state.seeded && state.next == sequence+1 {
+		state.next = sequence
+	}
+}
+
+// observe resync the sequence of the account of a submitted tx from its result. tefPAST_SEQ shows the
+// ledger is ahead, e.g. another sender used the sequence, so the account is reseeded. The other tef, tel
+// and tem results do not consume the sequence, which is given back if no later one was allocated. Other
+// txs may be in flight, so nothing else resets the account: terPRE_SEQ is transient while an earlier tx
+// is on its way, and a caller abandoning a sequence must Reset
+func (this *SequenceManager) observe(txBlob string, engineResult string) {
+	if !notApplied(engineResult) {
+		return
+	}
+	tx, err := types.DeserializeTransaction(txBlob)
+	if err != nil {
+		return
+	}
+	if _, ticketed := types.GetTicketSequence(tx); ticketed {
+		return
+	}
+	base := tx.GetBase()
+	if engineResult == "tefPAST_SEQ" {
+		this.Reset(base.Account)
+		return
+	}
+	this.release(base.Account, base.Sequence)
+}
@@ -0,0 +1,235 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// LedgerRange is an inclusive range of ledger indexes
+type LedgerRange struct {
+	Start uint32
+	End   uint32
+}
+
+// CompleteLedgers are the ledger ranges a node holds, e.g. "32570-6595042,6595044"
+type CompleteLedgers []LedgerRange
+
+// ParseCompleteLedgers parse the complete_ledgers field of server_info, "empty" is no ledger
+func ParseCompleteLedgers(s string) (CompleteLedgers, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || s == "empty" {
+		return CompleteLedgers{}, nil
+	}
+	var ledgers CompleteLedgers
+	for _, part := range strings.Split(s, ",") {
+		bounds := strings.SplitN(strings.TrimSpace(part), "-", 2)
+		start, err := strconv.ParseUint(bounds[0], 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("ParseCompleteLedgers: invalid range %s, err: %s", part, err)
+		}
+		end := start
+		if len(bounds) == 2 {
+			if end, err = strconv.ParseUint(bounds[1], 10, 32); err != nil {
+				return nil, fmt.Errorf("ParseCompleteLedgers: invalid range %s, err: %s", part, err)
+			}
+		}
+		if end < start {
+			return nil, fmt.Errorf("ParseCompleteLedgers: invalid range %s", part)
+		}
+		ledgers = append(ledgers, LedgerRange{Start: uint32(start), End: uint32(end)})
+	}
+	return ledgers, nil
+}
+
+// Contains return whether index is within one of the ranges
+func (this CompleteLedgers) Contains(index uint32) bool {
+	for _, r := range this {
+		if index >= r.Start && index <= r.End {
+			return true
+		}
+	}
+	return false
+}
+
+func (this CompleteLedgers) String() string {
+	if len(this) == 0 {
+		return "empty"
+	}
+	parts := make([]string, 0, len(this))
+	for _, r := range this {
+		if r.Start == r.End {
+			parts = append(parts, strconv.FormatUint(uint64(r.Start), 10))
+		} else {
+			parts = append(parts, fmt.Sprintf("%d-%d", r.Start, r.End))
+		}
+	}
+	return strings.Join(parts, ",")
+}
+
+func (this CompleteLedgers) MarshalJSON() ([]byte, error) {
+	return json.Marshal(this.String())
+}
+
+func (this *CompleteLedgers) UnmarshalJSON(b []byte) error {
+	var s string
+	if err := json.Unmarshal(b, &s); err != nil {
+		return err
+	}
+	ledgers, err := ParseCompleteLedgers(s)
+	if err != nil {
+		return err
+	}
+	*this = ledgers
+	return nil
+}
+
+// synced server states, the node follows the network and serves current validated ledgers
+var syncedStates = map[string]bool{"full": true, "validating": true, "proposing": true}
+
+// ServerInfo is the info object of server_info, amounts are in XRP
+type ServerInfo struct {
+	BuildVersion     string               `json:"build_version"`
+	CompleteLedgers  CompleteLedgers      `json:"complete_ledgers"`
+	HostId           string               `json:"hostid"`
+	LoadFactor       float64              `json:"load_factor"`
+	Peers            uint32               `json:"peers"`
+	PubkeyNode       string               `json:"pubkey_node"`
+	ServerState      string               `json:"server_state"`
+	Uptime           uint64               `json:"uptime"`
+	AmendmentBlocked bool                 `json:"amendment_blocked"`
+	ValidationQuorum uint32               `json:"validation_quorum"`
+	ValidatedLedger  *ValidatedLedgerInfo `json:"validated_ledger,omitempty"`
+}
+
+// ValidatedLedgerInfo is the last validated ledger in server_info, Age is in seconds
+type ValidatedLedgerInfo struct {
+	Age            uint32  `json:"age"`
+	BaseFeeXrp     float64 `json:"base_fee_xrp"`
+	Hash           string  `json:"hash"`
+	ReserveBaseXrp float64 `json:"reserve_base_xrp"`
+	ReserveIncXrp  float64 `json:"reserve_inc_xrp"`
+	Seq            uint32  `json:"seq"`
+}
+
+// Synced return whether the node is in sync with the network
+func (this *ServerInfo) Synced() bool {
+	return syncedStates[this.ServerState] && this.ValidatedLedger != nil
+}
+
+// HasLedger return whether the node holds the ledger of index
+func (this *ServerInfo) HasLedger(index uint32) bool {
+	return this.CompleteLedgers.Contains(index)
+}
+
+// ServerState is the state object of server_state, amounts are in drops and the load factor in load_base units
+type ServerState struct {
+	BuildVersion     string                `json:"build_version"`
+	CompleteLedgers  CompleteLedgers       `json:"complete_ledgers"`
+	LoadBase         uint32                `json:"load_base"`
+	LoadFactor       uint32                `json:"load_factor"`
+	Peers            uint32                `json:"peers"`
+	PubkeyNode       string                `json:"pubkey_node"`
+	ServerState      string                `json:"server_state"`
+	Uptime           uint64                `json:"uptime"`
+	AmendmentBlocked bool                  `json:"amendment_blocked"`
+	ValidationQuorum uint32                `json:"validation_quorum"`
+	ValidatedLedger  *ValidatedLedgerState `json:"validated_ledger,omitempty"`
+}
+
+// ValidatedLedgerState is the last validated ledger in server_state, CloseTime is in ripple epoch seconds
+type ValidatedLedgerState struct {
+	BaseFee     uint64 `json:"base_fee"`
+	CloseTime   uint32 `json:"close_time"`
+	Hash        string `json:"hash"`
+	ReserveBase uint64 `json:"reserve_base"`
+	ReserveInc  uint64 `json:"reserve_inc"`
+	Seq         uint32 `json:"seq"`
+}
+
+// Synced return whether the node is in sync with the network
+func (this *ServerState) Synced() bool {
+	return syncedStates[this.ServerState] && this.ValidatedLedger != nil
+}
+
+// HasLedger return whether the node holds the ledger of index
+func (this *ServerState) HasLedger(index uint32) bool {
+	return this.CompleteLedgers.Contains(index)
+}
+
+type ServerInfoRes struct {
+	Result struct {
+		Info         *ServerInfo `json:"info"`
+		Status       string      `json:"status"`
+		ErrorMessage string      `json:"error_message"`
+	} `json:"result"`
+}
+
+type ServerStateRes struct {
+	Result struct {
+		State        *ServerState `json:"state"`
+		Status       string       `json:"status"`
+		ErrorMessage string       `json:"error_message"`
+	} `json:"result"`
+}
+
+// GetServerInfo return the human readable status of the node
+func (this *RpcClient) GetServerInfo() (*ServerInfo, error) {
+	respData, err := this.sendRpcRequest(RPC_SERVER_INFO, []interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("GetServerInfo: send req err: %s", err)
+	}
+	result := &ServerInfoRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerInfo: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" || result.Result.Info == nil {
+		return nil, fmt.Errorf("GetServerInfo, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result.Result.Info, nil
+}
+
+// GetServerState return the machine readable status of the node
+func (this *RpcClient) GetServerState() (*ServerState, error) {
+	respData, err := this.sendRpcRequest(RPC_SERVER_STATE, []interface{}{})
+	if err != nil {
+		return nil, fmt.Errorf("GetServerState: send req err: %s", err)
+	}
+	result := &ServerStateRes{}
+	err = json.Unmarshal(respData, result)
+	if err != nil {
+		return nil, fmt.Errorf("GetServerState: unmarshal resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" || result.Result.State == nil {
+		return nil, fmt.Errorf("GetServerState, resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	return result.Result.State, nil
+}
+
+// HasLedger return whether the node is synced and holds the ledger of index
+func (this *RpcClient) HasLedger(index uint32) (bool, error) {
+	state, err := this.GetServerState()
+	if err != nil {
+		return false, fmt.Errorf("HasLedger: %s", err)
+	}
+	return state.Synced() && state.HasLedger(index), nil
+}
@@ -0,0 +1,163 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+)
+
+// LedgerSpecifier select the ledger a read method works on, by shortcut, index or hash. The zero
+// value lets the node choose, which is the current open ledger for most methods
+type LedgerSpecifier struct {
+	LedgerIndex interface{} `json:"ledger_index,omitempty"`
+	LedgerHash  string      `json:"ledger_hash,omitempty"`
+}
+
+var (
+	// LedgerValidated is the latest ledger validated by consensus, reads from it are final
+	LedgerValidated = LedgerSpecifier{LedgerIndex: "validated"}
+	// LedgerClosed is the latest ledger closed by the node, it may not be validated yet
+	LedgerClosed = LedgerSpecifier{LedgerIndex: "closed"}
+	// LedgerCurrent is the open ledger of the node, its content is provisional
+	LedgerCurrent = LedgerSpecifier{LedgerIndex: "current"}
+)
+
+// LedgerAtIndex select the ledger of index
+func LedgerAtIndex(index uint32) LedgerSpecifier {
+	return LedgerSpecifier{LedgerIndex: index}
+}
+
+// LedgerAtHash select the ledger of hash
+func LedgerAtHash(hash string) LedgerSpecifier {
+	return LedgerSpecifier{LedgerHash: strings.ToUpper(hash)}
+}
+
+func (this LedgerSpecifier) String() string {
+	switch {
+	case this.LedgerHash != "":
+		return this.LedgerHash
+	case this.LedgerIndex != nil:
+		return fmt.Sprint(this.LedgerIndex)
+	default:
+		return "default"
+	}
+}
+
+// index return the ledger index the specifier selects, LedgerIndex may be of any integer kind
+func (this LedgerSpecifier) index() (uint32, bool) {
+	if this.LedgerIndex == nil {
+		return 0, false
+	}
+	v := reflect.ValueOf(this.LedgerIndex)
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if v.Int() < 0 || v.Int() > math.MaxUint32 {
+			return 0, false
+		}
+		return uint32(v.Int()), true
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		if v.Uint() > math.MaxUint32 {
+			return 0, false
+		}
+		return uint32(v.Uint()), true
+	default:
+		return 0, false
+	}
+}
+
+// fixed return whether the specifier always select the same ledger
+func (this LedgerSpecifier) fixed() bool {
+	if this.LedgerHash != "" {
+		return true
+	}
+	_, ok := this.index()
+	return ok
+}
+
+// check return an error if a tx found in ledger index, validated or not, is not in the ledger selected
+// by a shortcut or an index
+func (this LedgerSpecifier) check(validated bool, index uint32) error {
+	switch this.LedgerIndex {
+	case nil, "current":
+		return nil
+	case "validated":
+		if !validated {
+			return fmt.Errorf("tx is not validated")
+		}
+		return nil
+	case "closed":
+		if index == 0 {
+			return fmt.Errorf("tx is not in a closed ledger")
+		}
+		return nil
+	}
+	if selected, ok := this.index(); !ok || selected != index {
+		return fmt.Errorf("tx is in ledger %d, not in ledger %s", index, this)
+	}
+	return nil
+}
+
+// txLedger return the check of a tx found by GetTx or GetTxBinary against ledger. A ledger hash is
+// resolved to the index of its ledger, which must be validated as the tx. A specifier which can not
+// select a tx is rejected before the tx is requested
+func (this *RpcClient) txLedger(ledger []LedgerSpecifier) (func(validated bool, index uint32) error, error) {
+	spec := ledgerSpecifier(ledger)
+	if spec.LedgerHash == "" {
+		switch spec.LedgerIndex {
+		case nil, "current", "validated", "closed":
+			return spec.check, nil
+		}
+		if _, ok := spec.index(); !ok {
+			return nil, fmt.Errorf("ledger %s is not supported to select a tx", spec)
+		}
+		return spec.check, nil
+	}
+	respData, err := this.sendCachedRequest(ledgerCacheKey(&ledgerReqParam{LedgerSpecifier: spec}), RPC_LEDGER,
+		[]interface{}{ledgerReqParam{LedgerSpecifier: spec}}, nil)
+	if err != nil {
+		return nil, fmt.Errorf("send ledger req err: %s", err)
+	}
+	result := &heightResp{}
+	if err := json.Unmarshal(respData, result); err != nil {
+		return nil, fmt.Errorf("unmarshal ledger resp err: %s, origin resp is %s", err, Redact(respData))
+	}
+	if result.Result.Status != "success" {
+		return nil, fmt.Errorf("ledger resp failed, status: %s, error: %s", result.Result.Status, result.Result.ErrorMessage)
+	}
+	if !result.Result.Validated {
+		return nil, fmt.Errorf("ledger %s is not validated", spec)
+	}
+	selected := result.Result.LedgerIndex
+	return func(validated bool, index uint32) error {
+		if !validated || index != selected {
+			return fmt.Errorf("tx is not in validated ledger %s", spec)
+		}
+		return nil
+	}, nil
+}
+
+func ledgerSpecifier(ledger []LedgerSpecifier) LedgerSpecifier {
+	if len(ledger) == 0 {
+		return LedgerSpecifier{}
+	}
+	return ledger[0]
+}
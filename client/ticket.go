@@ -0,0 +1,184 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package client
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/polynetwork/ripple-sdk/types"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// GetTickets return the sequences of the tickets held by account, in ascending order
+func (this *RpcClient) GetTickets(account string, ledger ...LedgerSpecifier) ([]uint32, error) {
+	objects, err := this.GetAllAccountObjects(account, AccountObjectTicket, ledger...)
+	if err != nil {
+		return nil, fmt.Errorf("GetTickets: %s", err)
+	}
+	tickets := make([]uint32, 0, len(objects))
+	for _, object := range objects {
+		ticket, ok := object.(*data.Ticket)
+		if !ok || ticket.TicketSequence == nil {
+			return nil, fmt.Errorf("GetTickets: invalid ticket object %s", object.GetLedgerIndex())
+		}
+		tickets = append(tickets, *ticket.TicketSequence)
+	}
+	sort.Slice(tickets, func(i, j int) bool { return tickets[i] < tickets[j] })
+	return tickets, nil
+}
+
+// TicketPool hand out the tickets of a multisig account so that many txs can be signed and submitted
+// at the same time. A ticket is in use from Acquire until Sync sees it consumed on the validated ledger,
+// or until it is Released because its tx was abandoned before being submitted or Failed without
+// consuming it
+type TicketPool struct {
+	rpc       *RpcClient
+	account   data.Account
+	lock      sync.Mutex
+	available []uint32
+	inUse     map[uint32]bool
+}
+
+// NewTicketPool return the pool of the tickets of account on the validated ledger
+func (this *RpcClient) NewTicketPool(account string) (*TicketPool, error) {
+	owner, err := data.NewAccountFromAddress(account)
+	if err != nil {
+		return nil, fmt.Errorf("NewTicketPool: invalid account %s, err: %s", account, err)
+	}
+	pool := &TicketPool{
+		rpc:     this,
+		account: *owner,
+		inUse:   make(map[uint32]bool),
+	}
+	if err := pool.Sync(); err != nil {
+		return nil, fmt.Errorf("NewTicketPool: %s", err)
+	}
+	return pool, nil
+}
+
+// Sync reload the tickets from the validated ledger, picking up the tickets created since and
+// forgetting the tickets in use which have been consumed
+func (this *TicketPool) Sync() error {
+	tickets, err := this.rpc.GetTickets(this.account.String(), LedgerValidated)
+	if err != nil {
+		return fmt.Errorf("TicketPool.Sync: %s", err)
+	}
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	held := make(map[uint32]bool, len(tickets))
+	this.available = this.available[:0]
+	for _, ticket := range tickets {
+		held[ticket] = true
+		if !this.inUse[ticket] {
+			this.available = append(this.available, ticket)
+		}
+	}
+	for ticket := range this.inUse {
+		if !held[ticket] {
+			delete(this.inUse, ticket)
+		}
+	}
+	return nil
+}
+
+// Acquire take the lowest available ticket. The pool can not sign for the account, so when none is left
+// the caller must refill it: sign and submit the tx of NewTicketCreate, then Sync once it is validated
+func (this *TicketPool) Acquire() (uint32, error) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if len(this.available) == 0 {
+		return 0, fmt.Errorf("TicketPool.Acquire: no ticket available for %s", this.account)
+	}
+	ticket := this.available[0]
+	this.available = this.available[1:]
+	this.inUse[ticket] = true
+	return ticket, nil
+}
+
+// Release give back a ticket whose tx will not be submitted, a ticket whose tx was submitted is given
+// back by Failed, which checks the result did not consume it
+func (this *TicketPool) Release(ticket uint32) {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	if !this.inUse[ticket] {
+		return
+	}
+	delete(this.inUse, ticket)
+	i := sort.Search(len(this.available), func(i int) bool { return this.available[i] >= ticket })
+	this.available = append(this.available[:i], append([]uint32{ticket}, this.available[i:]...)...)
+}
+
+// Failed give back the ticket of a submitted tx whose engineResult shows it was not applied: the tef,
+// tel and tem results do not consume the ticket, which would otherwise stay in use since Sync still
+// finds it in the ledger. tesSUCCESS and the tec results consume it, and a ter result may still apply
+// the tx, so the ticket stays in use for them. A ticket found already consumed, e.g. by tefPAST_SEQ, is
+// dropped by the next Sync
+func (this *TicketPool) Failed(ticket uint32, engineResult string) {
+	if notApplied(engineResult) {
+		this.Release(ticket)
+	}
+}
+
+// notApplied return whether a tx submitted with engineResult can never be applied, so that it consumed
+// neither its sequence nor its ticket
+func notApplied(engineResult string) bool {
+	return strings.HasPrefix(engineResult, "tef") || strings.HasPrefix(engineResult, "tel") ||
+		strings.HasPrefix(engineResult, "tem")
+}
+
+// Available return the number of tickets not in use
+func (this *TicketPool) Available() int {
+	this.lock.Lock()
+	defer this.lock.Unlock()
+	return len(this.available)
+}
+
+// NewTicketCreate return a TicketCreate of count tickets for the account of the pool, its Sequence and
+// fee for signers signatures filled. Sync picks up the tickets once the signed tx is validated
+func (this *TicketPool) NewTicketCreate(count uint32, signers int) (*data.TicketCreate, error) {
+	ticketCreate, err := types.GenerateTicketCreate(this.account, count, data.Value{}, 0)
+	if err != nil {
+		return nil, fmt.Errorf("TicketPool.NewTicketCreate: %s", err)
+	}
+	if err := this.rpc.Autofill(ticketCreate, signers); err != nil {
+		return nil, fmt.Errorf("TicketPool.NewTicketCreate: %s", err)
+	}
+	return ticketCreate, nil
+}
+
+// NewPayment acquire a ticket for a payment of amount to the destination and fill its fee for signers
+// signatures, pass the raw tx of types.SerializeRawMultiSignTransaction to the signers
+func (this *TicketPool) NewPayment(to string, amount data.Amount, signers int) (*types.TicketPayment, error) {
+	destination, err := data.NewAccountFromAddress(to)
+	if err != nil {
+		return nil, fmt.Errorf("TicketPool.NewPayment: invalid destination %s, err: %s", to, err)
+	}
+	ticket, err := this.Acquire()
+	if err != nil {
+		return nil, fmt.Errorf("TicketPool.NewPayment: %s", err)
+	}
+	payment := types.GenerateTicketPayment(this.account, *destination, amount, data.Value{}, ticket)
+	if err := this.rpc.Autofill(payment, signers); err != nil {
+		this.Release(ticket)
+		return nil, fmt.Errorf("TicketPool.NewPayment: %s", err)
+	}
+	return payment, nil
+}
@@ -22,14 +22,21 @@ import (
 	"fmt"
 	"testing"
 
+	"github.com/polynetwork/ripple-sdk/client/clienttest"
 	"github.com/stretchr/testify/assert"
 )
 
 func TestRPC(t *testing.T) {
+	server := clienttest.NewServer()
+	defer server.Close()
+	_, err := server.FundAccount("rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF", 100000000)
+	assert.Nil(t, err)
+
 	sdk := NewRippleSdk()
-	sdk.NewRpcClient().SetAddress("https://s.altnet.rippletest.net:51234/")
+	sdk.NewRpcClient().SetAddress(server.URL)
 	r, err := sdk.GetRpcClient().GetAccountInfo("rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF")
 	assert.Nil(t, err)
+	assert.Equal(t, "rLi6oSF38EdP7mzhdccyxhfd8vp8FWbsWF", r.AccountData.Account.String())
 	temp, _ := json.Marshal(r)
 	fmt.Println(string(temp))
 }
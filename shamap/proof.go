@@ -0,0 +1,129 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shamap
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// ProofLevel is one inner node on the path from the root to a leaf, Siblings hold the
+// hashes of all the children except the one at Branch which is recomputed on verify
+type ProofLevel struct {
+	Branch   uint8
+	Siblings [16]data.Hash256
+}
+
+// Proof is a merkle inclusion proof of the leaf with Key, Path starts at the root
+type Proof struct {
+	Key  data.Hash256
+	Path []ProofLevel
+}
+
+// RootHash recompute the root hash from the hash of the leaf
+func (this *Proof) RootHash(leafHash data.Hash256) (data.Hash256, error) {
+	if len(this.Path) == 0 || len(this.Path) > 64 {
+		return data.Hash256{}, fmt.Errorf("Proof.RootHash: invalid path depth %d", len(this.Path))
+	}
+	hash := leafHash
+	for depth := len(this.Path) - 1; depth >= 0; depth-- {
+		level := this.Path[depth]
+		if level.Branch != branch(this.Key, depth) {
+			return data.Hash256{}, fmt.Errorf("Proof.RootHash: branch %d at depth %d does not match key %s",
+				level.Branch, depth, this.Key.String())
+		}
+		children := level.Siblings
+		children[level.Branch] = hash
+		hash = innerHash(&children)
+	}
+	return hash, nil
+}
+
+// Verify check that the leaf hash is included in the tree of root
+func (this *Proof) Verify(root, leafHash data.Hash256) error {
+	hash, err := this.RootHash(leafHash)
+	if err != nil {
+		return err
+	}
+	if hash != root {
+		return fmt.Errorf("Proof.Verify: root hash mismatch, expect: %s, computed: %s", root.String(), hash.String())
+	}
+	return nil
+}
+
+// Bytes encode the proof compactly as key, depth, and for every level the branch,
+// a 16 bit mask of the non empty siblings and their hashes
+func (this *Proof) Bytes() []byte {
+	buf := make([]byte, 0, 33+len(this.Path)*(3+32*2))
+	buf = append(buf, this.Key[:]...)
+	buf = append(buf, byte(len(this.Path)))
+	for _, level := range this.Path {
+		var mask uint16
+		for i, sibling := range level.Siblings {
+			if uint8(i) != level.Branch && !sibling.IsZero() {
+				mask |= 1 << uint(i)
+			}
+		}
+		buf = append(buf, level.Branch, byte(mask>>8), byte(mask))
+		for i, sibling := range level.Siblings {
+			if mask&(1<<uint(i)) != 0 {
+				buf = append(buf, sibling[:]...)
+			}
+		}
+	}
+	return buf
+}
+
+// NewProofFromBytes decode the proof encoded by Proof.Bytes
+func NewProofFromBytes(raw []byte) (*Proof, error) {
+	if len(raw) < 33 {
+		return nil, fmt.Errorf("NewProofFromBytes: proof too short")
+	}
+	proof := &Proof{}
+	copy(proof.Key[:], raw[:32])
+	depth := int(raw[32])
+	raw = raw[33:]
+	for i := 0; i < depth; i++ {
+		if len(raw) < 3 {
+			return nil, fmt.Errorf("NewProofFromBytes: level %d truncated", i)
+		}
+		level := ProofLevel{Branch: raw[0]}
+		if level.Branch > 15 {
+			return nil, fmt.Errorf("NewProofFromBytes: invalid branch %d at level %d", level.Branch, i)
+		}
+		mask := binary.BigEndian.Uint16(raw[1:3])
+		raw = raw[3:]
+		for j := range level.Siblings {
+			if mask&(1<<uint(j)) == 0 {
+				continue
+			}
+			if len(raw) < 32 {
+				return nil, fmt.Errorf("NewProofFromBytes: level %d truncated", i)
+			}
+			copy(level.Siblings[j][:], raw[:32])
+			raw = raw[32:]
+		}
+		proof.Path = append(proof.Path, level)
+	}
+	if len(raw) != 0 {
+		return nil, fmt.Errorf("NewProofFromBytes: %d trailing bytes", len(raw))
+	}
+	return proof, nil
+}
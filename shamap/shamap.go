@@ -0,0 +1,205 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+// Package shamap implements the radix-16 merkle tree used by ripple ledgers for the
+// transaction and account state trees, together with compact inclusion proofs.
+package shamap
+
+import (
+	"crypto/sha512"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// Item is a leaf of the SHAMap
+type Item interface {
+	Key() data.Hash256
+	Hash() data.Hash256
+}
+
+type node interface {
+	hash() data.Hash256
+}
+
+type innerNode struct {
+	children [16]node
+	cached   *data.Hash256
+}
+
+type leafNode struct {
+	item Item
+}
+
+func (n *leafNode) hash() data.Hash256 {
+	return n.item.Hash()
+}
+
+func (n *innerNode) hash() data.Hash256 {
+	if n.cached != nil {
+		return *n.cached
+	}
+	var children [16]data.Hash256
+	for i, child := range n.children {
+		if child != nil {
+			children[i] = child.hash()
+		}
+	}
+	hash := innerHash(&children)
+	n.cached = &hash
+	return hash
+}
+
+// SHAMap is an in memory SHAMap, the root hash equals the ledger transaction_hash or account_hash
+// when filled with all the items of the corresponding ledger tree
+type SHAMap struct {
+	root  *innerNode
+	count int
+}
+
+func NewSHAMap() *SHAMap {
+	return &SHAMap{root: &innerNode{}}
+}
+
+// Add insert the item into the map, the key must not already exist
+func (this *SHAMap) Add(item Item) error {
+	key := item.Key()
+	parent := this.root
+	for depth := 0; ; depth++ {
+		parent.cached = nil
+		b := branch(key, depth)
+		switch child := parent.children[b].(type) {
+		case nil:
+			parent.children[b] = &leafNode{item: item}
+			this.count++
+			return nil
+		case *innerNode:
+			parent = child
+		case *leafNode:
+			if child.item.Key() == key {
+				return fmt.Errorf("SHAMap.Add: duplicate key %s", key.String())
+			}
+			inner := &innerNode{}
+			inner.children[branch(child.item.Key(), depth+1)] = child
+			parent.children[b] = inner
+			parent = inner
+		}
+	}
+}
+
+// Hash return the root hash, an empty map hashes to zero
+func (this *SHAMap) Hash() data.Hash256 {
+	if this.count == 0 {
+		return data.Hash256{}
+	}
+	return this.root.hash()
+}
+
+// Len return the number of items in the map
+func (this *SHAMap) Len() int {
+	return this.count
+}
+
+// Get return the item of key, or nil if not exist
+func (this *SHAMap) Get(key data.Hash256) Item {
+	leaf, _ := this.walk(key)
+	if leaf == nil {
+		return nil
+	}
+	return leaf.item
+}
+
+// Proof build the inclusion proof of the item with key
+func (this *SHAMap) Proof(key data.Hash256) (*Proof, error) {
+	leaf, path := this.walk(key)
+	if leaf == nil {
+		return nil, fmt.Errorf("SHAMap.Proof: key %s not found", key.String())
+	}
+	proof := &Proof{Key: key}
+	for depth, inner := range path {
+		level := ProofLevel{Branch: branch(key, depth)}
+		for i, child := range inner.children {
+			if child != nil && uint8(i) != level.Branch {
+				level.Siblings[i] = child.hash()
+			}
+		}
+		proof.Path = append(proof.Path, level)
+	}
+	return proof, nil
+}
+
+func (this *SHAMap) walk(key data.Hash256) (*leafNode, []*innerNode) {
+	var path []*innerNode
+	inner := this.root
+	for depth := 0; ; depth++ {
+		path = append(path, inner)
+		switch child := inner.children[branch(key, depth)].(type) {
+		case *innerNode:
+			inner = child
+		case *leafNode:
+			if child.item.Key() != key {
+				return nil, nil
+			}
+			return child, path
+		default:
+			return nil, nil
+		}
+	}
+}
+
+// branch return the nibble of key used to select the child at depth
+func branch(key data.Hash256, depth int) uint8 {
+	b := key[depth/2]
+	if depth%2 == 0 {
+		return b >> 4
+	}
+	return b & 0x0f
+}
+
+func innerHash(children *[16]data.Hash256) data.Hash256 {
+	buf := make([]byte, 0, 4+16*32)
+	buf = append(buf, data.HP_INNER_NODE.Bytes()...)
+	for i := range children {
+		buf = append(buf, children[i][:]...)
+	}
+	return sha512Half(buf)
+}
+
+func sha512Half(msg ...[]byte) data.Hash256 {
+	hasher := sha512.New()
+	for _, m := range msg {
+		hasher.Write(m)
+	}
+	var hash data.Hash256
+	copy(hash[:], hasher.Sum(nil))
+	return hash
+}
+
+// variableLength prefix b with its ripple variable length encoding
+func variableLength(b []byte) []byte {
+	l := len(b)
+	switch {
+	case l <= 192:
+		return append([]byte{byte(l)}, b...)
+	case l <= 12480:
+		l -= 193
+		return append([]byte{byte(193 + (l >> 8)), byte(l)}, b...)
+	default:
+		l -= 12481
+		return append([]byte{byte(241 + (l >> 16)), byte(l >> 8), byte(l)}, b...)
+	}
+}
@@ -0,0 +1,91 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shamap
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadTransactionItems(t *testing.T) ([]*TransactionItem, data.Hash256) {
+	raw, err := ioutil.ReadFile("testdata/ledger_6917762_txs.json")
+	assert.Nil(t, err)
+	ledger := struct {
+		TransactionHash data.Hash256 `json:"transaction_hash"`
+		Transactions    []struct {
+			TxBlob string `json:"tx_blob"`
+			Meta   string `json:"meta"`
+		} `json:"transactions"`
+	}{}
+	assert.Nil(t, json.Unmarshal(raw, &ledger))
+	var items []*TransactionItem
+	for _, tx := range ledger.Transactions {
+		txBlob, err := hex.DecodeString(tx.TxBlob)
+		assert.Nil(t, err)
+		meta, err := hex.DecodeString(tx.Meta)
+		assert.Nil(t, err)
+		items = append(items, NewTransactionItem(txBlob, meta))
+	}
+	return items, ledger.TransactionHash
+}
+
+func TestTransactionMap(t *testing.T) {
+	items, transactionHash := loadTransactionItems(t)
+	m, err := NewTransactionMap(items)
+	assert.Nil(t, err)
+	assert.Equal(t, len(items), m.Len())
+	assert.Equal(t, transactionHash, m.Hash())
+	assert.NotNil(t, m.Add(items[0]))
+	assert.True(t, NewSHAMap().Hash().IsZero())
+}
+
+func TestTransactionProof(t *testing.T) {
+	items, transactionHash := loadTransactionItems(t)
+	m, err := NewTransactionMap(items)
+	assert.Nil(t, err)
+
+	for _, item := range items {
+		proof, err := NewTransactionProof(m, item.Key())
+		assert.Nil(t, err)
+		assert.Nil(t, proof.Verify(transactionHash))
+
+		decoded, err := NewProofFromBytes(proof.Proof.Bytes())
+		assert.Nil(t, err)
+		assert.Equal(t, proof.Proof, decoded)
+
+		txm, err := item.Transaction()
+		assert.Nil(t, err)
+		assert.Equal(t, item.Key(), *txm.GetHash())
+	}
+
+	proof, err := NewTransactionProof(m, items[0].Key())
+	assert.Nil(t, err)
+	proof.Meta = items[1].Meta
+	assert.NotNil(t, proof.Verify(transactionHash))
+	proof.Meta = items[0].Meta
+	proof.Proof.Key = items[1].Key()
+	assert.NotNil(t, proof.Verify(transactionHash))
+
+	_, err = NewTransactionProof(m, data.Hash256{})
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,105 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shamap
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// StateItem is a leaf of the account state tree, a serialized ledger entry with its index
+type StateItem struct {
+	Index data.Hash256
+	Blob  []byte
+}
+
+func NewStateItem(index data.Hash256, blob []byte) *StateItem {
+	return &StateItem{Index: index, Blob: blob}
+}
+
+// Key return the ledger entry index
+func (this *StateItem) Key() data.Hash256 {
+	return this.Index
+}
+
+func (this *StateItem) Hash() data.Hash256 {
+	return sha512Half(data.HP_LEAF_NODE.Bytes(), this.Blob, this.Index[:])
+}
+
+// LedgerEntry decode the ledger entry
+func (this *StateItem) LedgerEntry() (data.LedgerEntry, error) {
+	// ReadLedgerEntry expects the node format, the entry suffixed with its index
+	raw := append(append([]byte{}, this.Blob...), this.Index[:]...)
+	le, err := data.ReadLedgerEntry(bytes.NewReader(raw), this.Index)
+	if err != nil {
+		return nil, fmt.Errorf("StateItem.LedgerEntry: decode failed, err: %s", err)
+	}
+	return le, nil
+}
+
+// NewStateMap build the account state tree of a ledger, all the entries of the ledger
+// are required for the root to match account_hash
+func NewStateMap(items []*StateItem) (*SHAMap, error) {
+	m := NewSHAMap()
+	for _, item := range items {
+		if err := m.Add(item); err != nil {
+			return nil, fmt.Errorf("NewStateMap: %s", err)
+		}
+	}
+	return m, nil
+}
+
+// StateProof prove that a ledger entry is in the account state tree of a ledger
+type StateProof struct {
+	Blob  []byte
+	Proof *Proof
+}
+
+// NewStateProof build the proof of the ledger entry with index in the tree
+func NewStateProof(m *SHAMap, index data.Hash256) (*StateProof, error) {
+	item, ok := m.Get(index).(*StateItem)
+	if !ok {
+		return nil, fmt.Errorf("NewStateProof: ledger entry %s not found", index.String())
+	}
+	proof, err := m.Proof(index)
+	if err != nil {
+		return nil, fmt.Errorf("NewStateProof: %s", err)
+	}
+	return &StateProof{Blob: item.Blob, Proof: proof}, nil
+}
+
+// Index return the index of the proved ledger entry
+func (this *StateProof) Index() data.Hash256 {
+	return this.Proof.Key
+}
+
+// Verify check the proof against the account_hash of the ledger
+func (this *StateProof) Verify(accountHash data.Hash256) error {
+	item := NewStateItem(this.Proof.Key, this.Blob)
+	if err := this.Proof.Verify(accountHash, item.Hash()); err != nil {
+		return fmt.Errorf("StateProof.Verify: %s", err)
+	}
+	return nil
+}
+
+// LedgerEntry decode the proved ledger entry
+func (this *StateProof) LedgerEntry() (data.LedgerEntry, error) {
+	return NewStateItem(this.Proof.Key, this.Blob).LedgerEntry()
+}
@@ -0,0 +1,75 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shamap
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func loadStateItems(t *testing.T) []*StateItem {
+	raw, err := ioutil.ReadFile("testdata/ledger_38129_state.json")
+	assert.Nil(t, err)
+	ledger := struct {
+		State []struct {
+			Data  string `json:"data"`
+			Index string `json:"index"`
+		} `json:"state"`
+	}{}
+	assert.Nil(t, json.Unmarshal(raw, &ledger))
+	var items []*StateItem
+	for _, entry := range ledger.State {
+		blob, err := hex.DecodeString(entry.Data)
+		assert.Nil(t, err)
+		index, err := data.NewHash256(entry.Index)
+		assert.Nil(t, err)
+		items = append(items, NewStateItem(*index, blob))
+	}
+	return items
+}
+
+func TestStateProof(t *testing.T) {
+	items := loadStateItems(t)
+	m, err := NewStateMap(items)
+	assert.Nil(t, err)
+	accountHash := m.Hash()
+
+	for _, item := range items {
+		proof, err := NewStateProof(m, item.Key())
+		assert.Nil(t, err)
+		assert.Nil(t, proof.Verify(accountHash))
+
+		le, err := proof.LedgerEntry()
+		assert.Nil(t, err)
+		if root, ok := le.(*data.AccountRoot); ok {
+			index, err := data.GetAccountRootIndex(*root.Account)
+			assert.Nil(t, err)
+			assert.Equal(t, item.Key(), *index)
+		}
+	}
+
+	proof, err := NewStateProof(m, items[0].Key())
+	assert.Nil(t, err)
+	proof.Blob = items[1].Blob
+	assert.NotNil(t, proof.Verify(accountHash))
+}
@@ -0,0 +1,102 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package shamap
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// TransactionItem is a leaf of the transaction tree, a transaction with its metadata
+type TransactionItem struct {
+	TxBlob []byte
+	Meta   []byte
+	hash   data.Hash256
+}
+
+func NewTransactionItem(txBlob, meta []byte) *TransactionItem {
+	return &TransactionItem{
+		TxBlob: txBlob,
+		Meta:   meta,
+		hash:   sha512Half(data.HP_TRANSACTION_ID.Bytes(), txBlob),
+	}
+}
+
+// Key return the transaction id
+func (this *TransactionItem) Key() data.Hash256 {
+	return this.hash
+}
+
+func (this *TransactionItem) Hash() data.Hash256 {
+	return sha512Half(data.HP_TRANSACTION_NODE.Bytes(), variableLength(this.TxBlob), variableLength(this.Meta), this.hash[:])
+}
+
+// Transaction decode the transaction and metadata
+func (this *TransactionItem) Transaction() (*data.TransactionWithMetaData, error) {
+	txm, err := data.ReadTransactionAndMetadata(bytes.NewReader(this.TxBlob), bytes.NewReader(this.Meta), this.hash, 0)
+	if err != nil {
+		return nil, fmt.Errorf("TransactionItem.Transaction: decode failed, err: %s", err)
+	}
+	return txm, nil
+}
+
+// NewTransactionMap build the transaction tree of a ledger
+func NewTransactionMap(items []*TransactionItem) (*SHAMap, error) {
+	m := NewSHAMap()
+	for _, item := range items {
+		if err := m.Add(item); err != nil {
+			return nil, fmt.Errorf("NewTransactionMap: %s", err)
+		}
+	}
+	return m, nil
+}
+
+// TransactionProof prove that a transaction and its metadata are in the transaction tree of a ledger
+type TransactionProof struct {
+	TxBlob []byte
+	Meta   []byte
+	Proof  *Proof
+}
+
+// NewTransactionProof build the proof of transaction txId in the tree
+func NewTransactionProof(m *SHAMap, txId data.Hash256) (*TransactionProof, error) {
+	item, ok := m.Get(txId).(*TransactionItem)
+	if !ok {
+		return nil, fmt.Errorf("NewTransactionProof: transaction %s not found", txId.String())
+	}
+	proof, err := m.Proof(txId)
+	if err != nil {
+		return nil, fmt.Errorf("NewTransactionProof: %s", err)
+	}
+	return &TransactionProof{TxBlob: item.TxBlob, Meta: item.Meta, Proof: proof}, nil
+}
+
+// Verify check the proof against the transaction_hash of the ledger
+func (this *TransactionProof) Verify(transactionHash data.Hash256) error {
+	item := NewTransactionItem(this.TxBlob, this.Meta)
+	if item.Key() != this.Proof.Key {
+		return fmt.Errorf("TransactionProof.Verify: tx id %s does not match proof key %s",
+			item.Key().String(), this.Proof.Key.String())
+	}
+	if err := this.Proof.Verify(transactionHash, item.Hash()); err != nil {
+		return fmt.Errorf("TransactionProof.Verify: %s", err)
+	}
+	return nil
+}
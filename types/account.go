@@ -18,8 +18,10 @@
 package types
 
 import (
+	"encoding/hex"
 	"fmt"
 	"math/rand"
+	"strings"
 	"time"
 
 	"github.com/rubblelabs/ripple/crypto"
@@ -86,6 +88,38 @@ func NewAccount() (*Account, *Wallet, error) {
 		&Wallet{accountAddr.String(), accountSeed.String()}, nil
 }
 
+// SignTx sign tx with the key of the account, set its hash and return the tx blob to submit
+func (this *Account) SignTx(tx data.Transaction) (string, error) {
+	var signTxSequence uint32
+	tx.InitialiseForSigning()
+	copy(tx.GetPublicKey().Bytes(), this.Key.Public(&signTxSequence))
+	hash, msg, err := data.SigningHash(tx)
+	if err != nil {
+		return "", fmt.Errorf("SignTx: signing hash failed, err: %s", err)
+	}
+	sig, err := crypto.Sign(privateKey(this.Key, &signTxSequence), hash.Bytes(), append(tx.SigningPrefix().Bytes(), msg...))
+	if err != nil {
+		return "", fmt.Errorf("SignTx: sign tx failed, err: %s", err)
+	}
+	*tx.GetSignature() = data.VariableLength(sig)
+	txHash, raw, err := data.Raw(tx)
+	if err != nil {
+		return "", fmt.Errorf("SignTx: serialize tx failed, err: %s", err)
+	}
+	*tx.GetHash() = txHash
+	return strings.ToUpper(hex.EncodeToString(raw)), nil
+}
+
+// privateKey return the private key of key, the library drops the leading zeros of a secp256k1 key,
+// which then fails to sign, so it is padded back to 32 bytes
+func privateKey(key crypto.Key, sequence *uint32) []byte {
+	private := key.Private(sequence)
+	if public := key.Public(sequence); len(public) > 0 && public[0] == 0xED || len(private) >= 32 {
+		return private
+	}
+	return append(make([]byte, 32-len(private)), private...)
+}
+
 func (this *Account) MultiSignTx(rawTx string) (*data.Payment, error) {
 	payment, err := DeserializeRawMultiSignTx(rawTx)
 	if err != nil {
@@ -94,6 +128,41 @@ func (this *Account) MultiSignTx(rawTx string) (*data.Payment, error) {
 	return multiSignTx(payment, this.Key, this.Account)
 }
 
+// MultiSignTransaction is MultiSignTx for a raw tx of any type
+func (this *Account) MultiSignTransaction(rawTx string) (data.Transaction, error) {
+	tx, err := DeserializeRawMultiSignTransaction(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("MultiSignTransaction: deserialized tx failed, err: %s", err)
+	}
+	signer, err := multiSigner(tx, this.Key, this.Account)
+	if err != nil {
+		return nil, fmt.Errorf("MultiSignTransaction: %s", err)
+	}
+	AddSigners(tx, *signer)
+	return tx, nil
+}
+
+// multiSigner return the signature of account over tx, which must have no signers yet, since the
+// library counts Signers in the signing data
+func multiSigner(tx data.Transaction, key crypto.Key, account data.Account) (*data.Signer, error) {
+	var signTxSequence uint32
+	hash, msg, err := data.MultiSignHash(tx, account)
+	if err != nil {
+		return nil, fmt.Errorf("multiSigner: multi sign hash failed, err: %s", err)
+	}
+	sig, err := crypto.Sign(privateKey(key, &signTxSequence), hash.Bytes(), append(tx.SigningPrefix().Bytes(), msg...))
+	if err != nil {
+		return nil, fmt.Errorf("multiSigner: sign failed, err: %s", err)
+	}
+	signer := &data.Signer{}
+	signer.Signer.Account = account
+	signature := data.VariableLength(sig)
+	signer.Signer.TxnSignature = &signature
+	signer.Signer.SigningPubKey = new(data.PublicKey)
+	copy(signer.Signer.SigningPubKey[:], key.Public(&signTxSequence))
+	return signer, nil
+}
+
 func multiSignTx(tx *data.Payment, key crypto.Key, account data.Account) (*data.Payment, error) {
 	var signTxSequence uint32
 	err := data.MultiSign(tx, key, &signTxSequence, account)
@@ -104,11 +173,11 @@ func multiSignTx(tx *data.Payment, key crypto.Key, account data.Account) (*data.
 }
 
 func CheckMultiSign(rawTx string, signer data.Account, pk, signature []byte) error {
-	payment, err := DeserializeRawMultiSignTx(rawTx)
+	tx, err := DeserializeRawMultiSignTransaction(rawTx)
 	if err != nil {
 		return fmt.Errorf("CheckMultiSign: deserialized tx failed, err: %s", err)
 	}
-	ok, err := data.CheckMultiSignature(payment, signer, pk, signature)
+	ok, err := data.CheckMultiSignature(tx, signer, pk, signature)
 	if err != nil {
 		return fmt.Errorf("CheckMultiSign: data.CheckMultiSignature error: %s", err)
 	}
@@ -0,0 +1,197 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/binary"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+)
+
+// channelClaimPrefix is the hash prefix of the payload of a channel claim, "CLM\0"
+var channelClaimPrefix = []byte{'C', 'L', 'M', 0}
+
+// ChannelClaim authorize the destination of Channel to claim up to Amount drops in total from it,
+// Signature is by the key PublicKey the channel was created with
+type ChannelClaim struct {
+	Channel   data.Hash256
+	Amount    uint64
+	Signature []byte
+	PublicKey data.PublicKey
+}
+
+// ChannelClaimPayload return the data signed by a claim of amount drops from channel
+func ChannelClaimPayload(channel data.Hash256, amount uint64) []byte {
+	payload := make([]byte, len(channelClaimPrefix)+len(channel)+8)
+	copy(payload, channelClaimPrefix)
+	copy(payload[len(channelClaimPrefix):], channel[:])
+	binary.BigEndian.PutUint64(payload[len(channelClaimPrefix)+len(channel):], amount)
+	return payload
+}
+
+// PublicKey return the public key of the account, which a payment channel created by it signs claims with
+func (this *Account) PublicKey() data.PublicKey {
+	var sequence uint32
+	var publicKey data.PublicKey
+	copy(publicKey[:], this.Key.Public(&sequence))
+	return publicKey
+}
+
+// SignChannelClaim sign a claim of amount drops in total from channel, the channel must have been
+// created with the PublicKey of the account
+func (this *Account) SignChannelClaim(channel data.Hash256, amount uint64) (*ChannelClaim, error) {
+	var sequence uint32
+	payload := ChannelClaimPayload(channel, amount)
+	sig, err := crypto.Sign(privateKey(this.Key, &sequence), crypto.Sha512Half(payload), payload)
+	if err != nil {
+		return nil, fmt.Errorf("SignChannelClaim: sign claim failed, err: %s", err)
+	}
+	return &ChannelClaim{
+		Channel:   channel,
+		Amount:    amount,
+		Signature: sig,
+		PublicKey: this.PublicKey(),
+	}, nil
+}
+
+// Verify check the signature of the claim against its public key, which the receiver must also check
+// is the key of the channel
+func (this *ChannelClaim) Verify() error {
+	return VerifyChannelClaim(this.Channel, this.Amount, this.Signature, this.PublicKey.Bytes())
+}
+
+// VerifyChannelClaim check signature is by publicKey over a claim of amount drops from channel
+func VerifyChannelClaim(channel data.Hash256, amount uint64, signature, publicKey []byte) error {
+	if len(publicKey) == 0 || len(signature) == 0 {
+		return fmt.Errorf("VerifyChannelClaim: missing signature or public key")
+	}
+	payload := ChannelClaimPayload(channel, amount)
+	ok, err := crypto.Verify(publicKey, crypto.Sha512Half(payload), payload, signature)
+	if err != nil {
+		return fmt.Errorf("VerifyChannelClaim: verify failed, err: %s", err)
+	}
+	if !ok {
+		return fmt.Errorf("VerifyChannelClaim: signature mismatch")
+	}
+	return nil
+}
+
+// GeneratePaymentChannelCreate open a channel from account to destination holding amount of XRP.
+// Claims are signed by publicKey, and the source can only close the channel settleDelay seconds after
+// asking to. cancelAfter is the ripple time the channel expires at, 0 for none
+func GeneratePaymentChannelCreate(account, destination data.Account, amount data.Amount, settleDelay uint32, publicKey data.PublicKey, cancelAfter uint32, fee data.Value, sequence uint32) (*data.PaymentChannelCreate, error) {
+	if !amount.IsNative() || !isPositive(amount) {
+		return nil, fmt.Errorf("GeneratePaymentChannelCreate: amount %s is not a positive XRP amount", amount)
+	}
+	if account.Equals(destination) {
+		return nil, fmt.Errorf("GeneratePaymentChannelCreate: destination is the source account")
+	}
+	tx := &data.PaymentChannelCreate{
+		Amount:      amount,
+		Destination: destination,
+		SettleDelay: settleDelay,
+		PublicKey:   publicKey,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.PAYCHAN_CREATE,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if cancelAfter != 0 {
+		tx.CancelAfter = &cancelAfter
+	}
+	return tx, nil
+}
+
+// GeneratePaymentChannelFund add amount of XRP to channel, which only its source can do. expiration is
+// the new ripple time the channel expires at, 0 to keep it
+func GeneratePaymentChannelFund(account data.Account, channel data.Hash256, amount data.Amount, expiration uint32, fee data.Value, sequence uint32) (*data.PaymentChannelFund, error) {
+	if !amount.IsNative() || !isPositive(amount) {
+		return nil, fmt.Errorf("GeneratePaymentChannelFund: amount %s is not a positive XRP amount", amount)
+	}
+	tx := &data.PaymentChannelFund{
+		Channel: channel,
+		Amount:  amount,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.PAYCHAN_FUND,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if expiration != 0 {
+		tx.Expiration = &expiration
+	}
+	return tx, nil
+}
+
+// GeneratePaymentChannelClaim claim from channel so that balance drops in total have been delivered to
+// its destination, 0 to claim nothing. The destination needs claim, signed by the source for at least
+// balance, the source needs none. flags is 0, data.TxClose to close the channel or data.TxRenew
+func GeneratePaymentChannelClaim(account data.Account, channel data.Hash256, balance uint64, claim *ChannelClaim, flags data.TransactionFlag, fee data.Value, sequence uint32) (*data.PaymentChannelClaim, error) {
+	if flags&^(data.TxClose|data.TxRenew) != 0 {
+		return nil, fmt.Errorf("GeneratePaymentChannelClaim: invalid flags %#x", uint32(flags))
+	}
+	tx := &data.PaymentChannelClaim{Channel: channel}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.PAYCHAN_CLAIM,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if flags != 0 {
+		tx.Flags = &flags
+	}
+	if balance != 0 {
+		amount, err := dropsAmount(balance)
+		if err != nil {
+			return nil, fmt.Errorf("GeneratePaymentChannelClaim: %s", err)
+		}
+		tx.Balance = amount
+	}
+	if claim == nil {
+		return tx, nil
+	}
+	switch {
+	case claim.Channel != channel:
+		return nil, fmt.Errorf("GeneratePaymentChannelClaim: claim is for channel %s", claim.Channel)
+	case claim.Amount < balance:
+		return nil, fmt.Errorf("GeneratePaymentChannelClaim: balance %d is above the claim of %d", balance, claim.Amount)
+	}
+	if err := claim.Verify(); err != nil {
+		return nil, fmt.Errorf("GeneratePaymentChannelClaim: %s", err)
+	}
+	amount, err := dropsAmount(claim.Amount)
+	if err != nil {
+		return nil, fmt.Errorf("GeneratePaymentChannelClaim: %s", err)
+	}
+	signature, publicKey := data.VariableLength(claim.Signature), claim.PublicKey
+	tx.Amount, tx.Signature, tx.PublicKey = amount, &signature, &publicKey
+	return tx, nil
+}
+
+func dropsAmount(drops uint64) (*data.Amount, error) {
+	value, err := data.NewNativeValue(int64(drops))
+	if err != nil {
+		return nil, fmt.Errorf("invalid drops %d, err: %s", drops, err)
+	}
+	return &data.Amount{Value: value}, nil
+}
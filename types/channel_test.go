@@ -0,0 +1,117 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetPayChannelIndex(t *testing.T) {
+	account, err := data.NewAccountFromAddress("rDx69ebzbowuqztksVDmZXjizTd12BVr4x")
+	assert.Nil(t, err)
+	destination, err := data.NewAccountFromAddress("rLFtVprxUEfsH54eCWKsZrEQzMDsx1wqso")
+	assert.Nil(t, err)
+	assert.Equal(t, "E35708503B3C3143FB522D749AAFCC296E8060F0FB371A9A56FAE0B1ED127366", GetPayChannelIndex(*account, *destination, 82).String())
+}
+
+func TestChannelClaim(t *testing.T) {
+	source := newTestAccount(t, "source")
+	destination := newTestAccount(t, "destination")
+	channel := GetPayChannelIndex(source.Account, destination.Account, 7)
+	payload := ChannelClaimPayload(channel, 1000000)
+	assert.Equal(t, []byte("CLM\x00"), payload[:4])
+	assert.Equal(t, channel[:], payload[4:36])
+	assert.Equal(t, []byte{0, 0, 0, 0, 0, 0x0F, 0x42, 0x40}, payload[36:])
+
+	claim, err := source.SignChannelClaim(channel, 1000000)
+	assert.Nil(t, err)
+	assert.Nil(t, claim.Verify())
+	assert.Equal(t, source.PublicKey(), claim.PublicKey)
+	assert.NotNil(t, VerifyChannelClaim(channel, 1000001, claim.Signature, claim.PublicKey.Bytes()))
+	assert.NotNil(t, VerifyChannelClaim(GetPayChannelIndex(source.Account, destination.Account, 8), 1000000, claim.Signature, claim.PublicKey.Bytes()))
+	other := destination.PublicKey()
+	assert.NotNil(t, VerifyChannelClaim(channel, 1000000, claim.Signature, other.Bytes()))
+	assert.NotNil(t, VerifyChannelClaim(channel, 1000000, nil, claim.PublicKey.Bytes()))
+}
+
+func TestPaymentChannel(t *testing.T) {
+	source := newTestAccount(t, "source")
+	destination := newTestAccount(t, "destination")
+	amount, err := data.NewAmount("10000000")
+	assert.Nil(t, err)
+	fee, err := data.NewNativeValue(10)
+	assert.Nil(t, err)
+
+	_, err = GeneratePaymentChannelCreate(source.Account, source.Account, *amount, 3600, source.PublicKey(), 0, *fee, 5)
+	assert.NotNil(t, err)
+	iou, err := data.NewAmount("1/USD/" + destination.Account.String())
+	assert.Nil(t, err)
+	_, err = GeneratePaymentChannelCreate(source.Account, destination.Account, *iou, 3600, source.PublicKey(), 0, *fee, 5)
+	assert.NotNil(t, err)
+	create, err := GeneratePaymentChannelCreate(source.Account, destination.Account, *amount, 3600, source.PublicKey(), 1000, *fee, 5)
+	assert.Nil(t, err)
+	txBlob, err := source.SignTx(create)
+	assert.Nil(t, err)
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	decodedCreate, ok := decoded.(*data.PaymentChannelCreate)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(3600), decodedCreate.SettleDelay)
+	assert.Equal(t, source.PublicKey(), decodedCreate.PublicKey)
+	assert.Equal(t, uint32(1000), *decodedCreate.CancelAfter)
+	assert.Equal(t, create.Hash, *decoded.GetHash())
+
+	channel := GetPayChannelIndex(source.Account, destination.Account, 5)
+	fund, err := GeneratePaymentChannelFund(source.Account, channel, *amount, 2000, *fee, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(2000), *fund.Expiration)
+
+	claim, err := source.SignChannelClaim(channel, 3000000)
+	assert.Nil(t, err)
+	_, err = GeneratePaymentChannelClaim(destination.Account, channel, 3000001, claim, 0, *fee, 1)
+	assert.NotNil(t, err)
+	_, err = GeneratePaymentChannelClaim(destination.Account, GetPayChannelIndex(source.Account, destination.Account, 6), 3000000, claim, 0, *fee, 1)
+	assert.NotNil(t, err)
+	_, err = GeneratePaymentChannelClaim(destination.Account, channel, 3000000, claim, data.TxSetFreeze, *fee, 1)
+	assert.NotNil(t, err)
+	forged := *claim
+	forged.Amount = 4000000
+	_, err = GeneratePaymentChannelClaim(destination.Account, channel, 3000000, &forged, 0, *fee, 1)
+	assert.NotNil(t, err)
+	redeem, err := GeneratePaymentChannelClaim(destination.Account, channel, 2000000, claim, 0, *fee, 1)
+	assert.Nil(t, err)
+	txBlob, err = destination.SignTx(redeem)
+	assert.Nil(t, err)
+	decoded, err = DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	decodedClaim, ok := decoded.(*data.PaymentChannelClaim)
+	assert.True(t, ok)
+	assert.Equal(t, channel, decodedClaim.Channel)
+	assert.Equal(t, "2", decodedClaim.Balance.Value.String())
+	assert.Equal(t, "3", decodedClaim.Amount.Value.String())
+	assert.Nil(t, VerifyChannelClaim(decodedClaim.Channel, 3000000, decodedClaim.Signature.Bytes(), decodedClaim.PublicKey.Bytes()))
+
+	closing, err := GeneratePaymentChannelClaim(source.Account, channel, 0, nil, data.TxClose, *fee, 7)
+	assert.Nil(t, err)
+	assert.Nil(t, closing.Balance)
+	assert.Nil(t, closing.Signature)
+	assert.Equal(t, data.TxClose, *closing.Flags)
+}
@@ -0,0 +1,98 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// GenerateCheckCreate write a check from account that destination can cash for up to sendMax, XRP or an
+// issued currency. expiration is the ripple time the check expires at, 0 for none, and invoiceID an
+// optional reference for the destination. The CheckID is GetCheckIndex of account and the sequence
+// the tx is sent with
+func GenerateCheckCreate(account, destination data.Account, sendMax data.Amount, expiration uint32, invoiceID *data.Hash256, fee data.Value, sequence uint32) (*data.CheckCreate, error) {
+	if !isPositive(sendMax) {
+		return nil, fmt.Errorf("GenerateCheckCreate: sendMax %s is not positive", sendMax)
+	}
+	if account.Equals(destination) {
+		return nil, fmt.Errorf("GenerateCheckCreate: destination is the source account")
+	}
+	tx := &data.CheckCreate{
+		Destination: destination,
+		SendMax:     sendMax,
+		InvoiceID:   invoiceID,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.CHECK_CREATE,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if expiration != 0 {
+		tx.Expiration = &expiration
+	}
+	return tx, nil
+}
+
+// GenerateCheckCash cash exactly amount from the check checkID, account must be its destination and
+// amount in the currency of its SendMax
+func GenerateCheckCash(account data.Account, checkID data.Hash256, amount data.Amount, fee data.Value, sequence uint32) (*data.CheckCash, error) {
+	if !isPositive(amount) {
+		return nil, fmt.Errorf("GenerateCheckCash: amount %s is not positive", amount)
+	}
+	tx := newCheckCash(account, checkID, fee, sequence)
+	tx.Amount = &amount
+	return tx, nil
+}
+
+// GenerateCheckCashDeliverMin cash as much as possible from the check checkID, failing if less than
+// deliverMin can be delivered
+func GenerateCheckCashDeliverMin(account data.Account, checkID data.Hash256, deliverMin data.Amount, fee data.Value, sequence uint32) (*data.CheckCash, error) {
+	if !isPositive(deliverMin) {
+		return nil, fmt.Errorf("GenerateCheckCashDeliverMin: deliverMin %s is not positive", deliverMin)
+	}
+	tx := newCheckCash(account, checkID, fee, sequence)
+	tx.DeliverMin = &deliverMin
+	return tx, nil
+}
+
+func newCheckCash(account data.Account, checkID data.Hash256, fee data.Value, sequence uint32) *data.CheckCash {
+	tx := &data.CheckCash{CheckID: checkID}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.CHECK_CASH,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return tx
+}
+
+// GenerateCheckCancel remove the check checkID, which its source and destination can do at any time
+// and anyone else once it has expired
+func GenerateCheckCancel(account data.Account, checkID data.Hash256, fee data.Value, sequence uint32) *data.CheckCancel {
+	tx := &data.CheckCancel{CheckID: checkID}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.CHECK_CANCEL,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return tx
+}
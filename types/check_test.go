@@ -0,0 +1,77 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestCheck(t *testing.T) {
+	source := newTestAccount(t, "source")
+	destination := newTestAccount(t, "destination")
+	sendMax, err := data.NewAmount("1000000")
+	assert.Nil(t, err)
+	fee, err := data.NewNativeValue(10)
+	assert.Nil(t, err)
+	invoiceID := sha512Half([]byte("invoice"))
+
+	_, err = GenerateCheckCreate(source.Account, source.Account, *sendMax, 0, nil, *fee, 5)
+	assert.NotNil(t, err)
+	zero, err := data.NewAmount("0")
+	assert.Nil(t, err)
+	_, err = GenerateCheckCreate(source.Account, destination.Account, *zero, 0, nil, *fee, 5)
+	assert.NotNil(t, err)
+	create, err := GenerateCheckCreate(source.Account, destination.Account, *sendMax, 1000, &invoiceID, *fee, 5)
+	assert.Nil(t, err)
+	txBlob, err := source.SignTx(create)
+	assert.Nil(t, err)
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	decodedCreate, ok := decoded.(*data.CheckCreate)
+	assert.True(t, ok)
+	assert.Equal(t, destination.Account, decodedCreate.Destination)
+	assert.Equal(t, uint32(1000), *decodedCreate.Expiration)
+	assert.Equal(t, invoiceID, *decodedCreate.InvoiceID)
+	assert.Equal(t, create.Hash, *decoded.GetHash())
+
+	checkID := GetCheckIndex(source.Account, 5)
+	_, err = GenerateCheckCash(destination.Account, checkID, *zero, *fee, 1)
+	assert.NotNil(t, err)
+	cash, err := GenerateCheckCash(destination.Account, checkID, *sendMax, *fee, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, cash.DeliverMin)
+	txBlob, err = destination.SignTx(cash)
+	assert.Nil(t, err)
+	decoded, err = DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	decodedCash, ok := decoded.(*data.CheckCash)
+	assert.True(t, ok)
+	assert.Equal(t, checkID, decodedCash.CheckID)
+	assert.Equal(t, "1", decodedCash.Amount.Value.String())
+	cash, err = GenerateCheckCashDeliverMin(destination.Account, checkID, *sendMax, *fee, 1)
+	assert.Nil(t, err)
+	assert.Nil(t, cash.Amount)
+	assert.Equal(t, sendMax, cash.DeliverMin)
+
+	cancel := GenerateCheckCancel(source.Account, checkID, *fee, 6)
+	assert.Equal(t, data.CHECK_CANCEL, cancel.TransactionType)
+	assert.Equal(t, checkID, cancel.CheckID)
+}
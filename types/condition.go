@@ -0,0 +1,149 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"fmt"
+)
+
+// PREIMAGE-SHA-256 crypto-conditions, the only type rippled supports for escrows. The condition is the
+// DER encoding of the sha256 fingerprint of the preimage and its length as cost, and the fulfillment
+// is the DER encoding of the preimage
+const (
+	// PreimageSize is the size of the preimages GeneratePreimage returns
+	PreimageSize = 32
+	// MaxPreimageSize is the largest preimage the sdk encodes
+	MaxPreimageSize = 0xFFFF
+
+	tagPreimageSha256 = 0xA0 //tagPreimageSha256 is the context tag 0 of the PREIMAGE-SHA-256 type
+	tagFingerprint    = 0x80
+	tagCost           = 0x81
+)
+
+// GeneratePreimage return a random preimage, keep it secret until the escrow is to be finished
+func GeneratePreimage() ([]byte, error) {
+	preimage := make([]byte, PreimageSize)
+	if _, err := rand.Read(preimage); err != nil {
+		return nil, fmt.Errorf("GeneratePreimage: %s", err)
+	}
+	return preimage, nil
+}
+
+// PreimageCondition return the condition fulfilled by preimage
+func PreimageCondition(preimage []byte) ([]byte, error) {
+	if len(preimage) > MaxPreimageSize {
+		return nil, fmt.Errorf("PreimageCondition: preimage of %d bytes is too large", len(preimage))
+	}
+	fingerprint := sha256.Sum256(preimage)
+	body := derField(tagFingerprint, fingerprint[:])
+	body = append(body, derField(tagCost, derUint(uint64(len(preimage))))...)
+	return derField(tagPreimageSha256, body), nil
+}
+
+// PreimageFulfillment return the fulfillment revealing preimage
+func PreimageFulfillment(preimage []byte) ([]byte, error) {
+	if len(preimage) > MaxPreimageSize {
+		return nil, fmt.Errorf("PreimageFulfillment: preimage of %d bytes is too large", len(preimage))
+	}
+	return derField(tagPreimageSha256, derField(tagFingerprint, preimage)), nil
+}
+
+// ParsePreimageFulfillment return the preimage of a PREIMAGE-SHA-256 fulfillment
+func ParsePreimageFulfillment(fulfillment []byte) ([]byte, error) {
+	body, rest, err := derRead(tagPreimageSha256, fulfillment)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("ParsePreimageFulfillment: not a PREIMAGE-SHA-256 fulfillment")
+	}
+	preimage, rest, err := derRead(tagFingerprint, body)
+	if err != nil || len(rest) != 0 {
+		return nil, fmt.Errorf("ParsePreimageFulfillment: invalid preimage")
+	}
+	return preimage, nil
+}
+
+// VerifyFulfillment check fulfillment fulfills condition
+func VerifyFulfillment(condition, fulfillment []byte) error {
+	preimage, err := ParsePreimageFulfillment(fulfillment)
+	if err != nil {
+		return fmt.Errorf("VerifyFulfillment: %s", err)
+	}
+	expected, err := PreimageCondition(preimage)
+	if err != nil {
+		return fmt.Errorf("VerifyFulfillment: %s", err)
+	}
+	if !bytes.Equal(expected, condition) {
+		return fmt.Errorf("VerifyFulfillment: fulfillment does not match the condition")
+	}
+	return nil
+}
+
+func derField(tag byte, value []byte) []byte {
+	field := []byte{tag}
+	switch n := len(value); {
+	case n < 0x80:
+		field = append(field, byte(n))
+	case n <= 0xFF:
+		field = append(field, 0x81, byte(n))
+	default:
+		field = append(field, 0x82, byte(n>>8), byte(n))
+	}
+	return append(field, value...)
+}
+
+// derUint return the content of a DER integer holding n, which has a leading zero if its high bit is set
+func derUint(n uint64) []byte {
+	var buf []byte
+	for ; n > 0; n >>= 8 {
+		buf = append([]byte{byte(n)}, buf...)
+	}
+	if len(buf) == 0 || buf[0]&0x80 != 0 {
+		buf = append([]byte{0}, buf...)
+	}
+	return buf
+}
+
+// derRead read the field of tag at the start of raw, returning its value and the bytes after it
+func derRead(tag byte, raw []byte) ([]byte, []byte, error) {
+	if len(raw) < 2 || raw[0] != tag {
+		return nil, nil, fmt.Errorf("expected tag %X", tag)
+	}
+	n, pos := int(raw[1]), 2
+	switch raw[1] {
+	case 0x81:
+		if len(raw) < 3 {
+			return nil, nil, fmt.Errorf("length truncated")
+		}
+		n, pos = int(raw[2]), 3
+	case 0x82:
+		if len(raw) < 4 {
+			return nil, nil, fmt.Errorf("length truncated")
+		}
+		n, pos = int(raw[2])<<8|int(raw[3]), 4
+	default:
+		if n >= 0x80 {
+			return nil, nil, fmt.Errorf("unsupported length %X", raw[1])
+		}
+	}
+	if len(raw) < pos+n {
+		return nil, nil, fmt.Errorf("value truncated")
+	}
+	return raw[pos : pos+n], raw[pos+n:], nil
+}
@@ -0,0 +1,73 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestPreimageCondition(t *testing.T) {
+	// the empty preimage example of the crypto-conditions draft
+	condition, err := PreimageCondition(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A0258020E3B0C44298FC1C149AFBF4C8996FB92427AE41E4649B934CA495991B7852B855810100", strings.ToUpper(hex.EncodeToString(condition)))
+	fulfillment, err := PreimageFulfillment(nil)
+	assert.Nil(t, err)
+	assert.Equal(t, "A0028000", strings.ToUpper(hex.EncodeToString(fulfillment)))
+	assert.Nil(t, VerifyFulfillment(condition, fulfillment))
+
+	preimage, err := GeneratePreimage()
+	assert.Nil(t, err)
+	assert.Equal(t, PreimageSize, len(preimage))
+	condition, err = PreimageCondition(preimage)
+	assert.Nil(t, err)
+	assert.Equal(t, 39, len(condition))
+	assert.Equal(t, "810120", hex.EncodeToString(condition[36:]))
+	fulfillment, err = PreimageFulfillment(preimage)
+	assert.Nil(t, err)
+	assert.Equal(t, 36, len(fulfillment))
+	parsed, err := ParsePreimageFulfillment(fulfillment)
+	assert.Nil(t, err)
+	assert.Equal(t, preimage, parsed)
+	assert.Nil(t, VerifyFulfillment(condition, fulfillment))
+
+	other, err := GeneratePreimage()
+	assert.Nil(t, err)
+	otherFulfillment, err := PreimageFulfillment(other)
+	assert.Nil(t, err)
+	assert.NotNil(t, VerifyFulfillment(condition, otherFulfillment))
+	assert.NotNil(t, VerifyFulfillment(condition, fulfillment[:20]))
+	_, err = ParsePreimageFulfillment(append(fulfillment, 0))
+	assert.NotNil(t, err)
+
+	// long form lengths, and a cost with its high bit set
+	long := make([]byte, 200)
+	condition, err = PreimageCondition(long)
+	assert.Nil(t, err)
+	assert.Equal(t, "810200c8", hex.EncodeToString(condition[36:]))
+	fulfillment, err = PreimageFulfillment(long)
+	assert.Nil(t, err)
+	assert.Equal(t, "a081cb8081c8", hex.EncodeToString(fulfillment[:6]))
+	assert.Nil(t, VerifyFulfillment(condition, fulfillment))
+	_, err = PreimageCondition(make([]byte, MaxPreimageSize+1))
+	assert.NotNil(t, err)
+}
@@ -0,0 +1,149 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// rippleEpoch is the unix time of the ripple epoch, 2000-01-01T00:00:00Z
+const rippleEpoch = 946684800
+
+// EscrowCreate lock Amount of XRP until Destination finishes the escrow, after FinishAfter and with the
+// fulfillment of Condition if set, or Account cancels it after CancelAfter. The library EscrowCreate
+// has the Digest of an old amendment instead of the Condition
+type EscrowCreate struct {
+	data.TxBase
+	Destination    data.Account
+	Amount         data.Amount
+	Condition      *data.VariableLength `json:",omitempty"`
+	CancelAfter    *uint32              `json:",omitempty"`
+	FinishAfter    *uint32              `json:",omitempty"`
+	DestinationTag *uint32              `json:",omitempty"`
+}
+
+// EscrowFinish deliver the escrow created by Owner with OfferSequence to its destination. The library
+// EscrowFinish has the Method, Digest and Proof of an old amendment instead of the Condition and
+// Fulfillment
+type EscrowFinish struct {
+	data.TxBase
+	Owner         data.Account
+	OfferSequence uint32
+	Condition     *data.VariableLength `json:",omitempty"`
+	Fulfillment   *data.VariableLength `json:",omitempty"`
+}
+
+// ExtraFeeUnits return the base fees finishing with a fulfillment costs on top of the fee of the tx
+func (this *EscrowFinish) ExtraFeeUnits() uint64 {
+	if this.Fulfillment == nil {
+		return 0
+	}
+	return 32 + uint64(len(*this.Fulfillment))/16
+}
+
+// isPositive return whether amount is above zero, the IsPositive of the library is also true for zero
+func isPositive(amount data.Amount) bool {
+	return amount.IsPositive() && !amount.IsZero()
+}
+
+// ToRippleTime return the seconds since the ripple epoch of t, as FinishAfter and CancelAfter expect
+func ToRippleTime(t time.Time) uint32 {
+	return uint32(t.Unix() - rippleEpoch)
+}
+
+// GenerateEscrowCreate lock amount of XRP from account for destination. finishAfter and cancelAfter are
+// ripple times, 0 to leave them unset, and condition is a PreimageCondition or nil. The escrow needs a
+// finishAfter or a condition, and cancelAfter must be after finishAfter
+func GenerateEscrowCreate(account, destination data.Account, amount data.Amount, finishAfter, cancelAfter uint32, condition []byte, fee data.Value, sequence uint32) (*EscrowCreate, error) {
+	if !amount.IsNative() || !isPositive(amount) {
+		return nil, fmt.Errorf("GenerateEscrowCreate: amount %s is not a positive XRP amount", amount)
+	}
+	if finishAfter == 0 && condition == nil {
+		return nil, fmt.Errorf("GenerateEscrowCreate: the escrow needs a finishAfter or a condition")
+	}
+	if finishAfter != 0 && cancelAfter != 0 && cancelAfter <= finishAfter {
+		return nil, fmt.Errorf("GenerateEscrowCreate: cancelAfter %d is not after finishAfter %d", cancelAfter, finishAfter)
+	}
+	tx := &EscrowCreate{
+		Destination: destination,
+		Amount:      amount,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.ESCROW_CREATE,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if finishAfter != 0 {
+		tx.FinishAfter = &finishAfter
+	}
+	if cancelAfter != 0 {
+		tx.CancelAfter = &cancelAfter
+	}
+	if condition != nil {
+		vl := data.VariableLength(condition)
+		tx.Condition = &vl
+	}
+	return tx, nil
+}
+
+// GenerateEscrowFinish finish the escrow created by owner with offerSequence. The condition and
+// fulfillment are both nil for an escrow without condition, else fulfillment must fulfill condition.
+// Pay the ExtraFeeUnits of the tx on top of its fee
+func GenerateEscrowFinish(account, owner data.Account, offerSequence uint32, condition, fulfillment []byte, fee data.Value, sequence uint32) (*EscrowFinish, error) {
+	if (condition == nil) != (fulfillment == nil) {
+		return nil, fmt.Errorf("GenerateEscrowFinish: condition and fulfillment go together")
+	}
+	tx := &EscrowFinish{
+		Owner:         owner,
+		OfferSequence: offerSequence,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.ESCROW_FINISH,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if condition != nil {
+		if err := VerifyFulfillment(condition, fulfillment); err != nil {
+			return nil, fmt.Errorf("GenerateEscrowFinish: %s", err)
+		}
+		c, f := data.VariableLength(condition), data.VariableLength(fulfillment)
+		tx.Condition, tx.Fulfillment = &c, &f
+	}
+	return tx, nil
+}
+
+// GenerateEscrowCancel return the XRP of the escrow created by owner with offerSequence to owner, once
+// its CancelAfter has passed
+func GenerateEscrowCancel(account, owner data.Account, offerSequence uint32, fee data.Value, sequence uint32) *data.EscrowCancel {
+	tx := &data.EscrowCancel{
+		Owner:         owner,
+		OfferSequence: offerSequence,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.ESCROW_CANCEL,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return tx
+}
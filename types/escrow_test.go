@@ -0,0 +1,100 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEscrow(t *testing.T) {
+	owner := newTestAccount(t, "owner")
+	destination := newTestAccount(t, "destination")
+	signer := newTestAccount(t, "signer1")
+	amount, err := data.NewAmount("1000000")
+	assert.Nil(t, err)
+	fee, err := data.NewNativeValue(10)
+	assert.Nil(t, err)
+	preimage, err := GeneratePreimage()
+	assert.Nil(t, err)
+	condition, err := PreimageCondition(preimage)
+	assert.Nil(t, err)
+	fulfillment, err := PreimageFulfillment(preimage)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0), ToRippleTime(time.Unix(rippleEpoch, 0)))
+
+	_, err = GenerateEscrowCreate(owner.Account, destination.Account, *amount, 0, 100, nil, *fee, 5)
+	assert.NotNil(t, err)
+	_, err = GenerateEscrowCreate(owner.Account, destination.Account, *amount, 100, 100, nil, *fee, 5)
+	assert.NotNil(t, err)
+	iou, err := data.NewAmount("1/USD/" + destination.Account.String())
+	assert.Nil(t, err)
+	_, err = GenerateEscrowCreate(owner.Account, destination.Account, *iou, 100, 0, nil, *fee, 5)
+	assert.NotNil(t, err)
+	create, err := GenerateEscrowCreate(owner.Account, destination.Account, *amount, 100, 200, condition, *fee, 5)
+	assert.Nil(t, err)
+
+	txBlob, err := owner.SignTx(create)
+	assert.Nil(t, err)
+	// Condition is vl field 17
+	assert.True(t, strings.Contains(txBlob, "701127"))
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	decodedCreate, ok := decoded.(*EscrowCreate)
+	assert.True(t, ok)
+	assert.Equal(t, condition, decodedCreate.Condition.Bytes())
+	assert.Equal(t, uint32(100), *decodedCreate.FinishAfter)
+	assert.Equal(t, uint32(200), *decodedCreate.CancelAfter)
+	assert.Equal(t, create.Hash, *decoded.GetHash())
+	ok, err = data.CheckSignature(decoded)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	_, err = GenerateEscrowFinish(destination.Account, owner.Account, 5, condition, nil, *fee, 1)
+	assert.NotNil(t, err)
+	_, err = GenerateEscrowFinish(destination.Account, owner.Account, 5, condition, fulfillment[:10], *fee, 1)
+	assert.NotNil(t, err)
+	finish, err := GenerateEscrowFinish(destination.Account, owner.Account, 5, nil, nil, *fee, 1)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(0), finish.ExtraFeeUnits())
+	finish, err = GenerateEscrowFinish(owner.Account, owner.Account, 5, condition, fulfillment, *fee, 6)
+	assert.Nil(t, err)
+	assert.Equal(t, uint64(34), finish.ExtraFeeUnits())
+
+	// an escrow of a multisig account is finished with multi-signing
+	rawTx, err := SerializeRawMultiSignTransaction(finish)
+	assert.Nil(t, err)
+	signed, err := signer.MultiSignTransaction(rawTx)
+	assert.Nil(t, err)
+	signedFinish, ok := signed.(*EscrowFinish)
+	assert.True(t, ok)
+	assert.Equal(t, fulfillment, signedFinish.Fulfillment.Bytes())
+	s := signedFinish.Signers[0].Signer
+	assert.Nil(t, CheckMultiSign(rawTx, s.Account, s.SigningPubKey.Bytes(), *s.TxnSignature))
+
+	cancel := GenerateEscrowCancel(owner.Account, owner.Account, 5, *fee, 7)
+	txBlob, err = owner.SignTx(cancel)
+	assert.Nil(t, err)
+	decoded, err = DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(5), decoded.(*data.EscrowCancel).OfferSequence)
+}
@@ -0,0 +1,79 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/binary"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+const (
+	spaceCheck      uint16 = 0x0043 // 'C'
+	spaceSignerList uint16 = 0x0053 // 'S'
+	spaceTicket     uint16 = 0x0054 // 'T'
+	spaceOffer      uint16 = 0x006F // 'o'
+	spaceEscrow     uint16 = 0x0075 // 'u'
+	spacePayChannel uint16 = 0x0078 // 'x'
+)
+
+// GetSignerListIndex return the index of the signer list owned by account, rippled only
+// supports the signer list with id 0
+func GetSignerListIndex(account data.Account) data.Hash256 {
+	return accountIndex(spaceSignerList, account, 0)
+}
+
+// GetTicketIndex return the index of the ticket of account with sequence
+func GetTicketIndex(account data.Account, sequence uint32) data.Hash256 {
+	return accountIndex(spaceTicket, account, sequence)
+}
+
+// GetEscrowIndex return the index of the escrow created by account with the tx of sequence
+func GetEscrowIndex(account data.Account, sequence uint32) data.Hash256 {
+	return accountIndex(spaceEscrow, account, sequence)
+}
+
+// GetCheckIndex return the index of the check created by account with the tx of sequence, which is the
+// CheckID to cash or cancel it with
+func GetCheckIndex(account data.Account, sequence uint32) data.Hash256 {
+	return accountIndex(spaceCheck, account, sequence)
+}
+
+// GetOfferIndex return the index of the offer placed by account with the tx of sequence
+func GetOfferIndex(account data.Account, sequence uint32) data.Hash256 {
+	return accountIndex(spaceOffer, account, sequence)
+}
+
+// GetPayChannelIndex return the index of the payment channel from account to destination created with
+// the tx of sequence
+func GetPayChannelIndex(account, destination data.Account, sequence uint32) data.Hash256 {
+	buf := make([]byte, 2+20+20+4)
+	binary.BigEndian.PutUint16(buf, spacePayChannel)
+	copy(buf[2:], account[:])
+	copy(buf[22:], destination[:])
+	binary.BigEndian.PutUint32(buf[42:], sequence)
+	return sha512Half(buf)
+}
+
+func accountIndex(space uint16, account data.Account, sequence uint32) data.Hash256 {
+	buf := make([]byte, 2+20+4)
+	binary.BigEndian.PutUint16(buf, space)
+	copy(buf[2:], account[:])
+	binary.BigEndian.PutUint32(buf[22:], sequence)
+	return sha512Half(buf)
+}
@@ -0,0 +1,43 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetSignerListIndex(t *testing.T) {
+	account, err := data.NewAccountFromAddress("rf1BiGeXwwQoi8Z2ueFYTEXSwuJYfV2Jpn")
+	assert.Nil(t, err)
+	assert.Equal(t, "A9C28A28B85CD533217F5C0A0C7767666B093FA58A0F2D80026FCC4CD932DDC7", GetSignerListIndex(*account).String())
+}
+
+func TestGetCheckIndex(t *testing.T) {
+	account, err := data.NewAccountFromAddress("rUn84CUYbNjRoTQ6mSW7BVJPSVJNLb1QLo")
+	assert.Nil(t, err)
+	assert.Equal(t, "49647F0D748DC3FE26BDACBC57F251AADEFFF391403EC9BF87C97F67E9977FB0", GetCheckIndex(*account, 2).String())
+}
+
+func TestGetOfferIndex(t *testing.T) {
+	account, err := data.NewAccountFromAddress("rBqb89MRQJnMPq8wTwEbtz4kvxrEDfcYvt")
+	assert.Nil(t, err)
+	assert.Equal(t, "96F76F27D8A327FC48753167EC04A46AA0E382E6F57F32FD12274144D00F1797", GetOfferIndex(*account, 866).String())
+}
@@ -0,0 +1,104 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// LedgerHash recompute the ledger hash from the header fields, it is the sha512half of
+// 'LWR\0' followed by ledger_index, total_coins, parent_hash, transaction_hash, account_hash,
+// parent_close_time, close_time, close_time_resolution and close_flags
+func LedgerHash(header *data.LedgerHeader) (data.Hash256, error) {
+	hash, _, err := data.Raw(&data.Ledger{LedgerHeader: *header})
+	if err != nil {
+		return hash, fmt.Errorf("LedgerHash: serialize ledger header failed, err: %s", err)
+	}
+	return hash, nil
+}
+
+// VerifyLedgerHeader check that the hash of ledger matches its header fields
+func VerifyLedgerHeader(ledger *data.Ledger) error {
+	hash, err := LedgerHash(&ledger.LedgerHeader)
+	if err != nil {
+		return fmt.Errorf("VerifyLedgerHeader: %s", err)
+	}
+	if hash != ledger.Hash {
+		return fmt.Errorf("VerifyLedgerHeader: ledger %d hash mismatch, expect: %s, computed: %s",
+			ledger.LedgerSequence, ledger.Hash.String(), hash.String())
+	}
+	return nil
+}
+
+// VerifyLedgerChain check that both ledgers are self consistent and child is the direct successor of parent
+func VerifyLedgerChain(parent, child *data.Ledger) error {
+	if err := VerifyLedgerHeader(parent); err != nil {
+		return fmt.Errorf("VerifyLedgerChain: parent invalid, err: %s", err)
+	}
+	if err := VerifyLedgerHeader(child); err != nil {
+		return fmt.Errorf("VerifyLedgerChain: child invalid, err: %s", err)
+	}
+	if child.LedgerSequence != parent.LedgerSequence+1 {
+		return fmt.Errorf("VerifyLedgerChain: ledger %d is not the successor of ledger %d",
+			child.LedgerSequence, parent.LedgerSequence)
+	}
+	if child.PreviousLedger != parent.Hash {
+		return fmt.Errorf("VerifyLedgerChain: parent hash mismatch, expect: %s, got: %s",
+			parent.Hash.String(), child.PreviousLedger.String())
+	}
+	if child.ParentCloseTime != parent.CloseTime {
+		return fmt.Errorf("VerifyLedgerChain: parent close time mismatch, expect: %d, got: %d",
+			parent.CloseTime.Uint32(), child.ParentCloseTime.Uint32())
+	}
+	return nil
+}
+
+// VerifyLedgers check the parent hash chain of consecutive ledgers sorted by ascending index
+func VerifyLedgers(ledgers []*data.Ledger) error {
+	if len(ledgers) == 1 {
+		return VerifyLedgerHeader(ledgers[0])
+	}
+	for i := 1; i < len(ledgers); i++ {
+		if err := VerifyLedgerChain(ledgers[i-1], ledgers[i]); err != nil {
+			return fmt.Errorf("VerifyLedgers: %s", err)
+		}
+	}
+	return nil
+}
+
+// DeserializeLedgerHeader decode the ledger_data returned by the ledger method in binary mode
+func DeserializeLedgerHeader(ledgerData string) (*data.LedgerHeader, error) {
+	raw, err := hex.DecodeString(ledgerData)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeLedgerHeader: cannot decode ledger data, err: %s", err)
+	}
+	header := &data.LedgerHeader{}
+	if len(raw) != binary.Size(header) {
+		return nil, fmt.Errorf("DeserializeLedgerHeader: invalid ledger data length %d", len(raw))
+	}
+	err = binary.Read(bytes.NewReader(raw), binary.BigEndian, header)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeLedgerHeader: parse ledger data failed, err: %s", err)
+	}
+	return header, nil
+}
@@ -0,0 +1,85 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+const ledger32570 = `{
+	"accepted": true,
+	"account_hash": "3806AF8F22037DE598D30D38C8861FADF391171D26F7DE34ACFA038996EA6BEB",
+	"close_flags": 0,
+	"close_time": 410325670,
+	"close_time_resolution": 10,
+	"closed": true,
+	"hash": "4109C6F2045FC7EFF4CDE8F9905D19C28820D86304080FF886B299F0206E42B5",
+	"ledger_index": "32570",
+	"parent_close_time": 410325660,
+	"parent_hash": "60A01EBF11537D8394EA1235253293508BDA7131D5F8710EFE9413AA129653A2",
+	"total_coins": "99999999999996320",
+	"transaction_hash": "0000000000000000000000000000000000000000000000000000000000000000"
+}`
+
+func TestVerifyLedgerHeader(t *testing.T) {
+	ledger := &data.Ledger{}
+	err := json.Unmarshal([]byte(ledger32570), ledger)
+	assert.Nil(t, err)
+	assert.Nil(t, VerifyLedgerHeader(ledger))
+
+	ledger.TotalXRP++
+	assert.NotNil(t, VerifyLedgerHeader(ledger))
+}
+
+func TestVerifyLedgerChain(t *testing.T) {
+	parent := &data.Ledger{}
+	err := json.Unmarshal([]byte(ledger32570), parent)
+	assert.Nil(t, err)
+
+	child := &data.Ledger{LedgerHeader: parent.LedgerHeader}
+	child.LedgerSequence++
+	child.PreviousLedger = parent.Hash
+	child.ParentCloseTime = parent.CloseTime
+	child.CloseTime.SetUint32(parent.CloseTime.Uint32() + 10)
+	child.Hash, err = LedgerHash(&child.LedgerHeader)
+	assert.Nil(t, err)
+	assert.Nil(t, VerifyLedgers([]*data.Ledger{parent, child}))
+
+	child.PreviousLedger = parent.TransactionHash
+	child.Hash, err = LedgerHash(&child.LedgerHeader)
+	assert.Nil(t, err)
+	assert.NotNil(t, VerifyLedgerChain(parent, child))
+}
+
+func TestDeserializeLedgerHeader(t *testing.T) {
+	header, err := DeserializeLedgerHeader("00007F3A016345785D89F1A060A01EBF11537D8394EA1235253293508BDA7131D5F8710EFE9413AA129653A2" +
+		"00000000000000000000000000000000000000000000000000000000000000003806AF8F22037DE598D30D38C8861FADF391171D26F7DE34ACFA038996EA6BEB" +
+		"1875129C187512A60A00")
+	assert.Nil(t, err)
+	ledger := &data.Ledger{}
+	err = json.Unmarshal([]byte(ledger32570), ledger)
+	assert.Nil(t, err)
+	assert.Equal(t, ledger.LedgerHeader, *header)
+
+	_, err = DeserializeLedgerHeader("00007F3A")
+	assert.NotNil(t, err)
+}
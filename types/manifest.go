@@ -0,0 +1,165 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+)
+
+const (
+	sfSequence        = 4  // uint32
+	sfFlags           = 2  // uint32
+	sfLedgerSequence  = 6  // uint32
+	sfSigningTime     = 9  // uint32
+	sfLedgerHash      = 1  // hash256
+	sfPublicKey       = 1  // vl
+	sfSigningPubKey   = 3  // vl
+	sfSignature       = 6  // vl
+	sfDomain          = 7  // vl
+	sfMasterSignature = 18 // vl
+
+	// manifest with this sequence revoke the master key
+	RevokedManifestSequence uint32 = 0xFFFFFFFF
+)
+
+// HP_MANIFEST is the 'MAN' prefix of the manifest signing data
+const HP_MANIFEST data.HashPrefix = 0x4D414E00
+
+// Manifest binds the ephemeral signing key of a validator to its master key
+type Manifest struct {
+	MasterKey       []byte
+	Sequence        uint32
+	SigningKey      []byte
+	Domain          string
+	Signature       []byte
+	MasterSignature []byte
+	signingData     []byte
+}
+
+// ParseManifest decode the manifest in base64, as returned by the manifest method and
+// published in validator lists
+func ParseManifest(manifest string) (*Manifest, error) {
+	raw, err := base64.StdEncoding.DecodeString(manifest)
+	if err != nil {
+		return nil, fmt.Errorf("ParseManifest: cannot decode base64, err: %s", err)
+	}
+	return DeserializeManifest(raw)
+}
+
+// DeserializeManifest decode the serialized manifest
+func DeserializeManifest(raw []byte) (*Manifest, error) {
+	fields, err := parseSTObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeManifest: %s", err)
+	}
+	m := &Manifest{signingData: signingData(fields, sfSignature, sfMasterSignature)}
+	hasSequence := false
+	for _, field := range fields {
+		switch {
+		case field.is(stVL, sfPublicKey):
+			m.MasterKey = field.value
+		case field.is(stUint32, sfSequence):
+			m.Sequence = binary.BigEndian.Uint32(field.value)
+			hasSequence = true
+		case field.is(stVL, sfSigningPubKey):
+			m.SigningKey = field.value
+		case field.is(stVL, sfDomain):
+			m.Domain = string(field.value)
+		case field.is(stVL, sfSignature):
+			m.Signature = field.value
+		case field.is(stVL, sfMasterSignature):
+			m.MasterSignature = field.value
+		}
+	}
+	if len(m.MasterKey) != 33 || !hasSequence || len(m.MasterSignature) == 0 {
+		return nil, fmt.Errorf("DeserializeManifest: missing master key, sequence or master signature")
+	}
+	if !m.Revoked() && (len(m.SigningKey) != 33 || len(m.Signature) == 0) {
+		return nil, fmt.Errorf("DeserializeManifest: missing signing key or signature")
+	}
+	return m, nil
+}
+
+// Revoked return whether the manifest revoke the master key
+func (this *Manifest) Revoked() bool {
+	return this.Sequence == RevokedManifestSequence
+}
+
+// MasterKeyString return the master key in the n9 base58 format
+func (this *Manifest) MasterKeyString() string {
+	return NodePublicKeyString(this.MasterKey)
+}
+
+// Verify check the master signature, and the ephemeral signature unless the manifest is revoked
+func (this *Manifest) Verify() error {
+	msg := append(HP_MANIFEST.Bytes(), this.signingData...)
+	hash := sha512Half(msg)
+	if err := verifySignature(this.MasterKey, hash, msg, this.MasterSignature); err != nil {
+		return fmt.Errorf("Manifest.Verify: master signature of %s invalid, err: %s", this.MasterKeyString(), err)
+	}
+	if this.Revoked() {
+		return nil
+	}
+	if err := verifySignature(this.SigningKey, hash, msg, this.Signature); err != nil {
+		return fmt.Errorf("Manifest.Verify: signature of %s invalid, err: %s", this.MasterKeyString(), err)
+	}
+	return nil
+}
+
+// NodePublicKeyString encode a validator public key in the n9 base58 format
+func NodePublicKeyString(key []byte) string {
+	hash, err := crypto.NewNodePublicKey(key)
+	if err != nil {
+		return fmt.Sprintf("Bad node public key: %X", key)
+	}
+	return hash.String()
+}
+
+// ParseNodePublicKey decode a validator public key in the n9 base58 format
+func ParseNodePublicKey(key string) ([]byte, error) {
+	hash, err := crypto.NewRippleHashCheck(key, crypto.RIPPLE_NODE_PUBLIC)
+	if err != nil {
+		return nil, fmt.Errorf("ParseNodePublicKey: invalid node public key %s, err: %s", key, err)
+	}
+	return hash.Payload(), nil
+}
+
+func verifySignature(key []byte, hash data.Hash256, msg, signature []byte) error {
+	if len(key) == 0 {
+		return fmt.Errorf("empty public key")
+	}
+	ok, err := crypto.Verify(key, hash[:], msg, signature)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		return fmt.Errorf("signature mismatch")
+	}
+	return nil
+}
+
+func sha512Half(msg []byte) data.Hash256 {
+	var hash data.Hash256
+	copy(hash[:], crypto.Sha512Half(msg))
+	return hash
+}
@@ -0,0 +1,225 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// BalanceChange is the change a tx made to the balance of Account in Currency, positive for a gain.
+// Counterparty is the other side of the trust line holding an IOU balance, and zero for XRP
+type BalanceChange struct {
+	Account      data.Account
+	Counterparty data.Account
+	Currency     data.Currency
+	Change       data.Value
+	Balance      data.Value //Balance is the balance after the tx
+}
+
+// IsNative return whether the change is of the XRP balance
+func (this *BalanceChange) IsNative() bool {
+	return this.Currency.IsNative()
+}
+
+// ObjectChange is a ledger object a tx created or deleted, Fields are its NewFields when created and
+// its FinalFields when deleted
+type ObjectChange struct {
+	LedgerEntryType data.LedgerEntryType
+	LedgerIndex     data.Hash256
+	Fields          data.LedgerEntry
+}
+
+// TxChanges is what a tx changed in the ledger, as recorded in its metadata
+type TxChanges struct {
+	Hash     data.Hash256
+	Result   data.TransactionResult
+	Balances []BalanceChange
+	Created  []ObjectChange
+	Deleted  []ObjectChange
+}
+
+// ParseMetaData return the balance changes and the created and deleted objects of a tx from its
+// AffectedNodes, txm may come from GetTx, GetTxBinary or the expanded txs of GetLedger. The XRP change
+// of the sender includes the fee, which is charged even if the tx failed with a tec result
+func ParseMetaData(txm *data.TransactionWithMetaData) (*TxChanges, error) {
+	changes := &TxChanges{
+		Hash:   *txm.GetHash(),
+		Result: txm.MetaData.TransactionResult,
+	}
+	for _, effect := range txm.MetaData.AffectedNodes {
+		var node *data.AffectedNode
+		var final, previous data.LedgerEntry
+		switch {
+		case effect.CreatedNode != nil:
+			node, final = effect.CreatedNode, effect.CreatedNode.NewFields
+			changes.Created = append(changes.Created, objectChange(node, final))
+		case effect.ModifiedNode != nil:
+			node, final, previous = effect.ModifiedNode, effect.ModifiedNode.FinalFields, effect.ModifiedNode.PreviousFields
+		case effect.DeletedNode != nil:
+			node, final, previous = effect.DeletedNode, effect.DeletedNode.FinalFields, effect.DeletedNode.PreviousFields
+			changes.Deleted = append(changes.Deleted, objectChange(node, final))
+		default:
+			continue
+		}
+		// a modified or deleted node without previous fields kept its balance
+		if final == nil || (previous == nil && effect.CreatedNode == nil) {
+			continue
+		}
+		var err error
+		switch node.LedgerEntryType {
+		case data.ACCOUNT_ROOT:
+			err = changes.addAccountRoot(final, previous)
+		case data.RIPPLE_STATE:
+			err = changes.addRippleState(final, previous)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("ParseMetaData: node %s, err: %s", objectIndex(node), err)
+		}
+	}
+	sort.Slice(changes.Balances, func(i, j int) bool {
+		a, b := &changes.Balances[i], &changes.Balances[j]
+		if c := a.Account.Compare(b.Account); c != 0 {
+			return c < 0
+		}
+		if c := bytes.Compare(a.Currency[:], b.Currency[:]); c != 0 {
+			return c < 0
+		}
+		return a.Counterparty.Less(b.Counterparty)
+	})
+	return changes, nil
+}
+
+// BalanceChanges return the balance changes of account
+func (this *TxChanges) BalanceChanges(account data.Account) []BalanceChange {
+	var res []BalanceChange
+	for _, change := range this.Balances {
+		if change.Account.Equals(account) {
+			res = append(res, change)
+		}
+	}
+	return res
+}
+
+// XrpChange return the change of the XRP balance of account, zero if it did not change
+func (this *TxChanges) XrpChange(account data.Account) data.Value {
+	for _, change := range this.Balances {
+		if change.Account.Equals(account) && change.IsNative() {
+			return change.Change
+		}
+	}
+	zero, _ := data.NewNativeValue(0)
+	return *zero
+}
+
+func objectChange(node *data.AffectedNode, fields data.LedgerEntry) ObjectChange {
+	return ObjectChange{
+		LedgerEntryType: node.LedgerEntryType,
+		LedgerIndex:     objectIndex(node),
+		Fields:          fields,
+	}
+}
+
+func objectIndex(node *data.AffectedNode) data.Hash256 {
+	if node.LedgerIndex == nil {
+		return data.Hash256{}
+	}
+	return *node.LedgerIndex
+}
+
+// addAccountRoot add the XRP change of an account root, previous is nil for a created one
+func (this *TxChanges) addAccountRoot(final, previous data.LedgerEntry) error {
+	root, ok := final.(*data.AccountRoot)
+	if !ok || root.Account == nil {
+		return fmt.Errorf("invalid account root fields")
+	}
+	balance, _ := data.NewNativeValue(0)
+	if root.Balance != nil {
+		balance = root.Balance
+	}
+	from, _ := data.NewNativeValue(0)
+	if previous != nil {
+		before, ok := previous.(*data.AccountRoot)
+		if !ok {
+			return fmt.Errorf("invalid account root previous fields")
+		}
+		if before.Balance == nil {
+			return nil
+		}
+		from = before.Balance
+	}
+	change, err := balance.Subtract(*from)
+	if err != nil {
+		return err
+	}
+	if change.IsZero() {
+		return nil
+	}
+	this.Balances = append(this.Balances, BalanceChange{
+		Account: *root.Account,
+		Change:  *change,
+		Balance: *balance,
+	})
+	return nil
+}
+
+// addRippleState add the IOU changes of both sides of a trust line, whose balance is held from the low
+// side, previous is nil for a created one
+func (this *TxChanges) addRippleState(final, previous data.LedgerEntry) error {
+	state, ok := final.(*data.RippleState)
+	if !ok || state.Balance == nil || state.LowLimit == nil || state.HighLimit == nil {
+		return fmt.Errorf("invalid trust line fields")
+	}
+	from := state.Balance.ZeroClone().Value
+	if previous != nil {
+		before, ok := previous.(*data.RippleState)
+		if !ok {
+			return fmt.Errorf("invalid trust line previous fields")
+		}
+		if before.Balance == nil {
+			return nil
+		}
+		from = before.Balance.Value
+	}
+	change, err := state.Balance.Value.Subtract(*from)
+	if err != nil {
+		return err
+	}
+	if change.IsZero() {
+		return nil
+	}
+	currency := state.Balance.Currency
+	low, high := state.LowLimit.Issuer, state.HighLimit.Issuer
+	this.Balances = append(this.Balances, BalanceChange{
+		Account:      low,
+		Counterparty: high,
+		Currency:     currency,
+		Change:       *change,
+		Balance:      *state.Balance.Value,
+	}, BalanceChange{
+		Account:      high,
+		Counterparty: low,
+		Currency:     currency,
+		Change:       *change.Negate(),
+		Balance:      *state.Balance.Value.Negate(),
+	})
+	return nil
+}
@@ -0,0 +1,115 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+// iouPaymentJson is the tx json of a payment of 5 USD from low to high, which trust each other, and of
+// a trust line of high deleted by the payment
+const iouPaymentJson = `{
+	"TransactionType": "Payment",
+	"Account": "%[1]s",
+	"Destination": "%[2]s",
+	"Amount": {"currency": "USD", "issuer": "%[2]s", "value": "5"},
+	"Fee": "12",
+	"Sequence": 3,
+	"hash": "0F4E4D4C5DB6B4C38E9F3F9F3E8C5A2B8B4A0B3C2D1E0F9A8B7C6D5E4F3A2B1C",
+	"ledger_index": 10,
+	"meta": {
+		"TransactionIndex": 0,
+		"TransactionResult": "tesSUCCESS",
+		"AffectedNodes": [
+			{"ModifiedNode": {
+				"LedgerEntryType": "AccountRoot",
+				"LedgerIndex": "1111111111111111111111111111111111111111111111111111111111111111",
+				"FinalFields": {"Account": "%[1]s", "Balance": "99999988", "Sequence": 4, "OwnerCount": 1, "Flags": 0},
+				"PreviousFields": {"Balance": "100000000", "Sequence": 3}
+			}},
+			{"ModifiedNode": {
+				"LedgerEntryType": "AccountRoot",
+				"LedgerIndex": "2222222222222222222222222222222222222222222222222222222222222222",
+				"FinalFields": {"Account": "%[2]s", "Balance": "50000000", "Sequence": 1, "OwnerCount": 0, "Flags": 0},
+				"PreviousFields": {"OwnerCount": 1}
+			}},
+			{"ModifiedNode": {
+				"LedgerEntryType": "RippleState",
+				"LedgerIndex": "3333333333333333333333333333333333333333333333333333333333333333",
+				"FinalFields": {
+					"Balance": {"currency": "USD", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "-15"},
+					"LowLimit": {"currency": "USD", "issuer": "%[1]s", "value": "0"},
+					"HighLimit": {"currency": "USD", "issuer": "%[2]s", "value": "100"},
+					"Flags": 0
+				},
+				"PreviousFields": {"Balance": {"currency": "USD", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "-10"}}
+			}},
+			{"DeletedNode": {
+				"LedgerEntryType": "RippleState",
+				"LedgerIndex": "4444444444444444444444444444444444444444444444444444444444444444",
+				"FinalFields": {
+					"Balance": {"currency": "EUR", "issuer": "rrrrrrrrrrrrrrrrrrrrBZbvji", "value": "0"},
+					"LowLimit": {"currency": "EUR", "issuer": "%[1]s", "value": "0"},
+					"HighLimit": {"currency": "EUR", "issuer": "%[2]s", "value": "0"},
+					"Flags": 0
+				}
+			}}
+		]
+	}
+}`
+
+func TestParseMetaData(t *testing.T) {
+	low := newTestAccount(t, "signer1").Account
+	high := newTestAccount(t, "signer2").Account
+	if high.Less(low) {
+		low, high = high, low
+	}
+	txm := &data.TransactionWithMetaData{}
+	assert.Nil(t, json.Unmarshal([]byte(fmt.Sprintf(iouPaymentJson, low, high)), txm))
+
+	changes, err := ParseMetaData(txm)
+	assert.Nil(t, err)
+	assert.Equal(t, 3, len(changes.Balances))
+	fee := changes.XrpChange(low)
+	assert.Equal(t, "-0.000012", fee.String())
+	unchanged := changes.XrpChange(high)
+	assert.True(t, unchanged.IsZero())
+
+	sent := changes.BalanceChanges(low)
+	assert.Equal(t, 2, len(sent))
+	assert.True(t, sent[0].IsNative())
+	assert.Equal(t, "USD", sent[1].Currency.String())
+	assert.Equal(t, high, sent[1].Counterparty)
+	assert.Equal(t, "-5", sent[1].Change.String())
+	assert.Equal(t, "-15", sent[1].Balance.String())
+	received := changes.BalanceChanges(high)
+	assert.Equal(t, 1, len(received))
+	assert.Equal(t, low, received[0].Counterparty)
+	assert.Equal(t, "5", received[0].Change.String())
+	assert.Equal(t, "15", received[0].Balance.String())
+
+	assert.Equal(t, 0, len(changes.Created))
+	assert.Equal(t, 1, len(changes.Deleted))
+	assert.Equal(t, data.RIPPLE_STATE, changes.Deleted[0].LedgerEntryType)
+	assert.Equal(t, "4444444444444444444444444444444444444444444444444444444444444444", changes.Deleted[0].LedgerIndex.String())
+}
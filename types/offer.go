@@ -0,0 +1,154 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// DropsPerXrp is the number of drops in one XRP
+const DropsPerXrp = 1000000
+
+// offerFlags are the flags of an OfferCreate: data.TxPassive to not consume offers at the exact same
+// quality, data.TxImmediateOrCancel and data.TxFillOrKill to never rest in the book, the latter only if
+// fully filled, and data.TxSell to exchange all of TakerGets even for more than TakerPays
+const offerFlags = data.TxPassive | data.TxImmediateOrCancel | data.TxFillOrKill | data.TxSell
+
+// GenerateOfferCreate place an offer from account to give takerGets in exchange for takerPays. flags
+// combines the offerFlags, expiration is the ripple time the offer expires at and offerSequence the
+// sequence of an offer of account to replace, 0 for none
+func GenerateOfferCreate(account data.Account, takerPays, takerGets data.Amount, flags data.TransactionFlag, expiration, offerSequence uint32, fee data.Value, sequence uint32) (*data.OfferCreate, error) {
+	switch {
+	case flags&^offerFlags != 0:
+		return nil, fmt.Errorf("GenerateOfferCreate: invalid flags %#x", uint32(flags))
+	case flags&data.TxImmediateOrCancel != 0 && flags&data.TxFillOrKill != 0:
+		return nil, fmt.Errorf("GenerateOfferCreate: an offer can not be both immediate or cancel and fill or kill")
+	case !isPositive(takerPays) || !isPositive(takerGets):
+		return nil, fmt.Errorf("GenerateOfferCreate: takerPays %s and takerGets %s must be positive", takerPays, takerGets)
+	case takerPays.Asset().String() == takerGets.Asset().String():
+		return nil, fmt.Errorf("GenerateOfferCreate: takerPays and takerGets are both %s", takerPays.Asset())
+	}
+	tx := &data.OfferCreate{
+		TakerPays: takerPays,
+		TakerGets: takerGets,
+	}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.OFFER_CREATE,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	if flags != 0 {
+		tx.Flags = &flags
+	}
+	if expiration != 0 {
+		tx.Expiration = &expiration
+	}
+	if offerSequence != 0 {
+		tx.OfferSequence = &offerSequence
+	}
+	return tx, nil
+}
+
+// GenerateOfferCancel remove the offer account placed with the tx of offerSequence
+func GenerateOfferCancel(account data.Account, offerSequence uint32, fee data.Value, sequence uint32) *data.OfferCancel {
+	tx := &data.OfferCancel{OfferSequence: offerSequence}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.OFFER_CANCEL,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return tx
+}
+
+// Quality is the price of an offer as rippled ranks the order book, what the taker pays for one unit
+// of what the taker gets, counting XRP in drops. A lower quality is a better offer for the taker
+type Quality struct {
+	rat *big.Rat
+}
+
+// OfferQuality return the quality of an offer of takerGets for takerPays
+func OfferQuality(takerPays, takerGets data.Amount) (*Quality, error) {
+	if takerGets.IsZero() {
+		return nil, fmt.Errorf("OfferQuality: takerGets is zero")
+	}
+	return &Quality{rat: new(big.Rat).Quo(takerPays.Rat(), takerGets.Rat())}, nil
+}
+
+// ParseQuality parse a quality as book_offers and account_offers report it
+func ParseQuality(text string) (*Quality, error) {
+	quality := &Quality{}
+	if err := quality.UnmarshalText([]byte(text)); err != nil {
+		return nil, err
+	}
+	return quality, nil
+}
+
+// Rat return the quality as a rational number
+func (this *Quality) Rat() *big.Rat {
+	if this.rat == nil {
+		return new(big.Rat)
+	}
+	return new(big.Rat).Set(this.rat)
+}
+
+// Price return the quality with XRP counted in XRP rather than drops, takerPaysXrp and takerGetsXrp
+// tell which side of the offer is XRP
+func (this *Quality) Price(takerPaysXrp, takerGetsXrp bool) *big.Rat {
+	price, drops := this.Rat(), big.NewRat(DropsPerXrp, 1)
+	if takerPaysXrp {
+		price.Quo(price, drops)
+	}
+	if takerGetsXrp {
+		price.Mul(price, drops)
+	}
+	return price
+}
+
+// Cmp compare two qualities, -1 if this is the better quality for the taker
+func (this *Quality) Cmp(other *Quality) int {
+	return this.Rat().Cmp(other.Rat())
+}
+
+// Float64 return the nearest float of the quality
+func (this *Quality) Float64() float64 {
+	f, _ := this.Rat().Float64()
+	return f
+}
+
+// String return the quality with the 16 significant digits of a rippled amount
+func (this *Quality) String() string {
+	return new(big.Float).SetPrec(128).SetRat(this.Rat()).Text('g', 16)
+}
+
+func (this Quality) MarshalText() ([]byte, error) {
+	return []byte(this.String()), nil
+}
+
+func (this *Quality) UnmarshalText(text []byte) error {
+	rat, ok := new(big.Rat).SetString(string(text))
+	if !ok || rat.Sign() < 0 {
+		return fmt.Errorf("invalid quality %q", text)
+	}
+	this.rat = rat
+	return nil
+}
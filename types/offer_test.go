@@ -0,0 +1,118 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestOffer(t *testing.T) {
+	account := newTestAccount(t, "trader")
+	issuer := newTestAccount(t, "issuer")
+	xrp, err := data.NewAmount("25000000")
+	assert.Nil(t, err)
+	usd, err := data.NewAmount("10/USD/" + issuer.Account.String())
+	assert.Nil(t, err)
+	fee, err := data.NewNativeValue(10)
+	assert.Nil(t, err)
+
+	_, err = GenerateOfferCreate(account.Account, *xrp, *usd, data.TxImmediateOrCancel|data.TxFillOrKill, 0, 0, *fee, 5)
+	assert.NotNil(t, err)
+	_, err = GenerateOfferCreate(account.Account, *xrp, *usd, data.TxSetFreeze, 0, 0, *fee, 5)
+	assert.NotNil(t, err)
+	_, err = GenerateOfferCreate(account.Account, *xrp, *xrp, 0, 0, 0, *fee, 5)
+	assert.NotNil(t, err)
+	zero, err := data.NewAmount("0")
+	assert.Nil(t, err)
+	_, err = GenerateOfferCreate(account.Account, *zero, *usd, 0, 0, 0, *fee, 5)
+	assert.NotNil(t, err)
+
+	create, err := GenerateOfferCreate(account.Account, *xrp, *usd, data.TxPassive|data.TxSell, 1000, 3, *fee, 5)
+	assert.Nil(t, err)
+	txBlob, err := account.SignTx(create)
+	assert.Nil(t, err)
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	decodedCreate, ok := decoded.(*data.OfferCreate)
+	assert.True(t, ok)
+	assert.Equal(t, data.TxPassive|data.TxSell, *decodedCreate.Flags&^data.TxCanonicalSignature)
+	assert.Equal(t, uint32(1000), *decodedCreate.Expiration)
+	assert.Equal(t, uint32(3), *decodedCreate.OfferSequence)
+	assert.Equal(t, "10/USD/"+issuer.Account.String(), decodedCreate.TakerGets.String())
+	assert.Equal(t, create.Hash, *decoded.GetHash())
+	create, err = GenerateOfferCreate(account.Account, *xrp, *usd, 0, 0, 0, *fee, 5)
+	assert.Nil(t, err)
+	assert.Nil(t, create.Flags)
+	assert.Nil(t, create.Expiration)
+	assert.Nil(t, create.OfferSequence)
+
+	cancel := GenerateOfferCancel(account.Account, 5, *fee, 6)
+	assert.Equal(t, data.OFFER_CANCEL, cancel.TransactionType)
+	assert.Equal(t, uint32(5), cancel.OfferSequence)
+}
+
+func TestQuality(t *testing.T) {
+	issuer := newTestAccount(t, "issuer")
+	xrp, err := data.NewAmount("25000000")
+	assert.Nil(t, err)
+	usd, err := data.NewAmount("10/USD/" + issuer.Account.String())
+	assert.Nil(t, err)
+
+	// 25 XRP for 10 USD is 2500000 drops per USD
+	quality, err := OfferQuality(*xrp, *usd)
+	assert.Nil(t, err)
+	assert.Equal(t, big.NewRat(2500000, 1), quality.Rat())
+	assert.Equal(t, "2500000", quality.String())
+	assert.Equal(t, big.NewRat(5, 2), quality.Price(true, false))
+	inverse, err := OfferQuality(*usd, *xrp)
+	assert.Nil(t, err)
+	assert.Equal(t, "4e-07", inverse.String())
+	assert.Equal(t, big.NewRat(2, 5), inverse.Price(false, true))
+	assert.Equal(t, 4e-7, inverse.Float64())
+	zero, err := data.NewAmount("0")
+	assert.Nil(t, err)
+	_, err = OfferQuality(*xrp, *zero)
+	assert.NotNil(t, err)
+
+	// rippled reports small qualities in scientific notation
+	parsed, err := ParseQuality("4e-7")
+	assert.Nil(t, err)
+	assert.Equal(t, 0, parsed.Cmp(inverse))
+	parsed, err = ParseQuality("2500000.5")
+	assert.Nil(t, err)
+	assert.Equal(t, 1, parsed.Cmp(quality))
+	assert.Equal(t, -1, quality.Cmp(parsed))
+	_, err = ParseQuality("-1")
+	assert.NotNil(t, err)
+	_, err = ParseQuality("quality")
+	assert.NotNil(t, err)
+
+	var offer struct {
+		Quality Quality `json:"quality"`
+	}
+	assert.Nil(t, json.Unmarshal([]byte(`{"quality":"0.0000004"}`), &offer))
+	assert.Equal(t, 0, offer.Quality.Cmp(inverse))
+	text, err := json.Marshal(offer)
+	assert.Nil(t, err)
+	assert.Equal(t, `{"quality":"4e-07"}`, string(text))
+}
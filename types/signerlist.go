@@ -0,0 +1,147 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+const (
+	// MaxSignerEntries is the most signers a signer list may hold
+	MaxSignerEntries = 32
+	// AsfDisableMaster is the AccountSet flag disabling the master key of an account
+	AsfDisableMaster uint32 = 4
+)
+
+// SignerEntry is one weighted signer of a signer list. The library SignerEntry is not wrapped in its
+// inner object, so it is neither the json rippled expects nor the canonical binary format
+type SignerEntry struct {
+	SignerEntry struct {
+		Account      data.Account
+		SignerWeight uint16
+	}
+}
+
+// SignerListSet set, replace or, with quorum 0 and no entries, delete the signer list of Account
+type SignerListSet struct {
+	data.TxBase
+	SignerQuorum  uint32
+	SignerEntries []SignerEntry `json:",omitempty"`
+}
+
+func NewSignerEntry(account data.Account, weight uint16) SignerEntry {
+	entry := SignerEntry{}
+	entry.SignerEntry.Account = account
+	entry.SignerEntry.SignerWeight = weight
+	return entry
+}
+
+// ValidateSignerList check the signer list of account can be set: 1 to MaxSignerEntries distinct signers
+// other than account, each with a weight, and a quorum the signers can reach. Quorum 0 with no entries
+// is the deletion of the list
+func ValidateSignerList(account data.Account, quorum uint32, entries []SignerEntry) error {
+	if quorum == 0 && len(entries) == 0 {
+		return nil
+	}
+	if quorum == 0 {
+		return fmt.Errorf("ValidateSignerList: quorum must be positive")
+	}
+	if len(entries) == 0 || len(entries) > MaxSignerEntries {
+		return fmt.Errorf("ValidateSignerList: %d signers, expected 1 to %d", len(entries), MaxSignerEntries)
+	}
+	seen := make(map[data.Account]bool, len(entries))
+	var total uint32
+	for _, entry := range entries {
+		signer := entry.SignerEntry.Account
+		if signer.Equals(account) {
+			return fmt.Errorf("ValidateSignerList: account %s can not be its own signer", account)
+		}
+		if seen[signer] {
+			return fmt.Errorf("ValidateSignerList: duplicate signer %s", signer)
+		}
+		if entry.SignerEntry.SignerWeight == 0 {
+			return fmt.Errorf("ValidateSignerList: signer %s has no weight", signer)
+		}
+		seen[signer] = true
+		total += uint32(entry.SignerEntry.SignerWeight)
+	}
+	if total < quorum {
+		return fmt.Errorf("ValidateSignerList: quorum %d is above the total weight %d", quorum, total)
+	}
+	return nil
+}
+
+// GenerateSignerListSet return the SignerListSet of account after validating the signer list, the
+// entries are sorted by account
+func GenerateSignerListSet(account data.Account, quorum uint32, entries []SignerEntry, fee data.Value, sequence uint32) (*SignerListSet, error) {
+	if err := ValidateSignerList(account, quorum, entries); err != nil {
+		return nil, fmt.Errorf("GenerateSignerListSet: %s", err)
+	}
+	sorted := append([]SignerEntry{}, entries...)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].SignerEntry.Account[:], sorted[j].SignerEntry.Account[:]) < 0
+	})
+	signerListSet := &SignerListSet{
+		SignerQuorum:  quorum,
+		SignerEntries: sorted,
+	}
+	signerListSet.TxBase = data.TxBase{
+		TransactionType: data.SIGNER_LIST_SET,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return signerListSet, nil
+}
+
+// GenerateAccountSet return the AccountSet of account setting the flag setFlag, e.g. AsfDisableMaster
+func GenerateAccountSet(account data.Account, setFlag uint32, fee data.Value, sequence uint32) *data.AccountSet {
+	accountSet := &data.AccountSet{
+		SetFlag: &setFlag,
+	}
+	accountSet.TxBase = data.TxBase{
+		TransactionType: data.ACCOUNT_SET,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return accountSet
+}
+
+// SignerList is the signer list object of an account in the json of rippled, whose entries the
+// library does not decode
+type SignerList struct {
+	SignerQuorum  uint32
+	SignerEntries []SignerEntry
+	SignerListID  uint32
+	Index         string `json:"index"`
+}
+
+// Weight return the weight of signer in the list, 0 if it is not a signer
+func (this *SignerList) Weight(signer data.Account) uint16 {
+	for _, entry := range this.SignerEntries {
+		if entry.SignerEntry.Account.Equals(signer) {
+			return entry.SignerEntry.SignerWeight
+		}
+	}
+	return 0
+}
@@ -0,0 +1,92 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/hex"
+	"strings"
+	"testing"
+
+	"github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func newTestAccount(t *testing.T, passphrase string) *Account {
+	seed, err := crypto.GenerateFamilySeed(passphrase)
+	assert.Nil(t, err)
+	account, err := ImportAccount(seed.String())
+	assert.Nil(t, err)
+	return account
+}
+
+func TestValidateSignerList(t *testing.T) {
+	owner := newTestAccount(t, "owner").Account
+	signer1 := newTestAccount(t, "signer1").Account
+	signer2 := newTestAccount(t, "signer2").Account
+	entries := []SignerEntry{NewSignerEntry(signer1, 1), NewSignerEntry(signer2, 2)}
+
+	assert.Nil(t, ValidateSignerList(owner, 3, entries))
+	assert.Nil(t, ValidateSignerList(owner, 0, nil))
+	assert.NotNil(t, ValidateSignerList(owner, 0, entries))
+	assert.NotNil(t, ValidateSignerList(owner, 4, entries))
+	assert.NotNil(t, ValidateSignerList(owner, 1, append(entries, NewSignerEntry(signer1, 1))))
+	assert.NotNil(t, ValidateSignerList(owner, 1, append(entries, NewSignerEntry(owner, 1))))
+	assert.NotNil(t, ValidateSignerList(owner, 1, []SignerEntry{NewSignerEntry(signer1, 0)}))
+
+	many := make([]SignerEntry, 0, MaxSignerEntries+1)
+	for i := 0; i <= MaxSignerEntries; i++ {
+		var signer data.Account
+		signer[0] = byte(i + 1)
+		many = append(many, NewSignerEntry(signer, 1))
+	}
+	assert.NotNil(t, ValidateSignerList(owner, 1, many))
+	assert.Nil(t, ValidateSignerList(owner, 1, many[:MaxSignerEntries]))
+}
+
+func TestSignerListSet(t *testing.T) {
+	owner := newTestAccount(t, "owner")
+	signer := newTestAccount(t, "signer1").Account
+	fee, err := data.NewNativeValue(10)
+	assert.Nil(t, err)
+	tx, err := GenerateSignerListSet(owner.Account, 1, []SignerEntry{NewSignerEntry(signer, 1)}, *fee, 5)
+	assert.Nil(t, err)
+
+	txBlob, err := owner.SignTx(tx)
+	assert.Nil(t, err)
+	// each entry is wrapped in a SignerEntry object inside the SignerEntries array
+	entry := "F4EB130001" + "8114" + strings.ToUpper(hex.EncodeToString(signer[:])) + "E1F1"
+	assert.True(t, strings.Contains(txBlob, entry))
+
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	signerListSet, ok := decoded.(*SignerListSet)
+	assert.True(t, ok)
+	assert.Equal(t, tx.SignerEntries, signerListSet.SignerEntries)
+	assert.Equal(t, *tx.GetHash(), *signerListSet.GetHash())
+	ok, err = data.CheckSignature(signerListSet)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+
+	accountSet := GenerateAccountSet(owner.Account, AsfDisableMaster, *fee, 6)
+	_, err = owner.SignTx(accountSet)
+	assert.Nil(t, err)
+	ok, err = data.CheckSignature(accountSet)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
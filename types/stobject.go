@@ -0,0 +1,208 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"fmt"
+)
+
+const (
+	stUint16    = 1
+	stUint32    = 2
+	stUint64    = 3
+	stHash128   = 4
+	stHash256   = 5
+	stAmount    = 6
+	stVL        = 7
+	stAccount   = 8
+	stObject    = 14
+	stArray     = 15
+	stUint8     = 16
+	stHash160   = 17
+	stPathSet   = 18
+	stVector256 = 19
+)
+
+// stField is a top level field of a serialized object, raw holds the field header and the value
+type stField struct {
+	typ   int
+	code  int
+	raw   []byte
+	value []byte
+}
+
+func (this *stField) is(typ, code int) bool {
+	return this.typ == typ && this.code == code
+}
+
+// parseSTObject split a serialized object into its top level fields, keeping the exact bytes
+// so that signing data can be rebuilt without re-encoding
+func parseSTObject(raw []byte) ([]*stField, error) {
+	var fields []*stField
+	for len(raw) > 0 {
+		field, n, err := parseSTField(raw)
+		if err != nil {
+			return nil, err
+		}
+		fields = append(fields, field)
+		raw = raw[n:]
+	}
+	return fields, nil
+}
+
+func parseSTField(raw []byte) (*stField, int, error) {
+	typ, code, pos, err := parseFieldHeader(raw)
+	if err != nil {
+		return nil, 0, err
+	}
+	start := pos
+	switch typ {
+	case stUint8:
+		pos += 1
+	case stUint16:
+		pos += 2
+	case stUint32:
+		pos += 4
+	case stUint64:
+		pos += 8
+	case stHash128:
+		pos += 16
+	case stHash160:
+		pos += 20
+	case stHash256:
+		pos += 32
+	case stAmount:
+		if pos >= len(raw) {
+			return nil, 0, fmt.Errorf("parseSTField: amount truncated")
+		}
+		if raw[pos]&0x80 == 0 {
+			pos += 8
+		} else {
+			pos += 48
+		}
+	case stVL, stAccount, stVector256:
+		l, n, err := parseVariableLength(raw[pos:])
+		if err != nil {
+			return nil, 0, err
+		}
+		start = pos + n
+		pos += n + l
+	case stObject, stArray:
+		end := 0xE1
+		if typ == stArray {
+			end = 0xF1
+		}
+		for {
+			if pos >= len(raw) {
+				return nil, 0, fmt.Errorf("parseSTField: object truncated")
+			}
+			if int(raw[pos]) == end {
+				pos++
+				break
+			}
+			_, n, err := parseSTField(raw[pos:])
+			if err != nil {
+				return nil, 0, err
+			}
+			pos += n
+		}
+	case stPathSet:
+		for {
+			if pos >= len(raw) {
+				return nil, 0, fmt.Errorf("parseSTField: path set truncated")
+			}
+			b := raw[pos]
+			pos++
+			if b == 0x00 {
+				break
+			}
+			if b == 0xFF {
+				continue
+			}
+			pos += 20 * (int(b&0x01) + int(b&0x10>>4) + int(b&0x20>>5))
+		}
+	default:
+		return nil, 0, fmt.Errorf("parseSTField: unsupported field type %d", typ)
+	}
+	if pos > len(raw) {
+		return nil, 0, fmt.Errorf("parseSTField: field %d/%d truncated", typ, code)
+	}
+	return &stField{typ: typ, code: code, raw: raw[:pos], value: raw[start:pos]}, pos, nil
+}
+
+func parseFieldHeader(raw []byte) (int, int, int, error) {
+	if len(raw) == 0 {
+		return 0, 0, 0, fmt.Errorf("parseFieldHeader: empty field")
+	}
+	typ, code, pos := int(raw[0]>>4), int(raw[0]&0x0f), 1
+	if typ == 0 {
+		if pos >= len(raw) {
+			return 0, 0, 0, fmt.Errorf("parseFieldHeader: header truncated")
+		}
+		typ = int(raw[pos])
+		pos++
+	}
+	if code == 0 {
+		if pos >= len(raw) {
+			return 0, 0, 0, fmt.Errorf("parseFieldHeader: header truncated")
+		}
+		code = int(raw[pos])
+		pos++
+	}
+	return typ, code, pos, nil
+}
+
+func parseVariableLength(raw []byte) (int, int, error) {
+	if len(raw) == 0 {
+		return 0, 0, fmt.Errorf("parseVariableLength: length truncated")
+	}
+	b0 := int(raw[0])
+	switch {
+	case b0 <= 192:
+		return b0, 1, nil
+	case b0 <= 240:
+		if len(raw) < 2 {
+			return 0, 0, fmt.Errorf("parseVariableLength: length truncated")
+		}
+		return 193 + (b0-193)*256 + int(raw[1]), 2, nil
+	case b0 <= 254:
+		if len(raw) < 3 {
+			return 0, 0, fmt.Errorf("parseVariableLength: length truncated")
+		}
+		return 12481 + (b0-241)*65536 + int(raw[1])*256 + int(raw[2]), 3, nil
+	default:
+		return 0, 0, fmt.Errorf("parseVariableLength: invalid length prefix %d", b0)
+	}
+}
+
+// signingData concatenate the fields which are not signatures
+func signingData(fields []*stField, signatures ...int) []byte {
+	var buf []byte
+	for _, field := range fields {
+		skip := false
+		for _, code := range signatures {
+			if field.is(stVL, code) {
+				skip = true
+			}
+		}
+		if !skip {
+			buf = append(buf, field.raw...)
+		}
+	}
+	return buf
+}
@@ -0,0 +1,132 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// MaxTickets is the most tickets an account may hold, and so create at once
+const MaxTickets = 250
+
+// TicketPayment is a payment consuming a ticket instead of a sequence, its Sequence is 0. The library
+// Payment has no TicketSequence field, so that many payments of an account can be signed at the same time
+type TicketPayment struct {
+	data.Payment
+	TicketSequence uint32
+}
+
+// GetTicketSequence return the ticket tx consumes
+func (this *TicketPayment) GetTicketSequence() uint32 {
+	return this.TicketSequence
+}
+
+// TicketTx is a tx of any type consuming a ticket instead of a sequence, its Sequence is 0. The library
+// txs have no TicketSequence field, the library encoder follows the embedded tx so that TicketSequence
+// is serialized with its fields
+type TicketTx struct {
+	data.Transaction
+	TicketSequence uint32
+}
+
+// NewTicketTx return tx consuming ticket, the Sequence of tx is cleared
+func NewTicketTx(tx data.Transaction, ticket uint32) *TicketTx {
+	tx.GetBase().Sequence = 0
+	return &TicketTx{Transaction: tx, TicketSequence: ticket}
+}
+
+// GetTicketSequence return the ticket tx consumes
+func (this *TicketTx) GetTicketSequence() uint32 {
+	return this.TicketSequence
+}
+
+// MarshalJSON return the tx_json of the tx with its TicketSequence
+func (this *TicketTx) MarshalJSON() ([]byte, error) {
+	raw, err := json.Marshal(this.Transaction)
+	if err != nil {
+		return nil, err
+	}
+	fields := make(map[string]json.RawMessage)
+	if err := json.Unmarshal(raw, &fields); err != nil {
+		return nil, err
+	}
+	if fields["TicketSequence"], err = json.Marshal(this.TicketSequence); err != nil {
+		return nil, err
+	}
+	return json.Marshal(fields)
+}
+
+// Unticketed return the tx a TicketTx or TicketPayment wraps, tx itself otherwise
+func Unticketed(tx data.Transaction) data.Transaction {
+	switch v := tx.(type) {
+	case *TicketTx:
+		return v.Transaction
+	case *TicketPayment:
+		return &v.Payment
+	default:
+		return tx
+	}
+}
+
+type ticketTx interface {
+	GetTicketSequence() uint32
+}
+
+// GetTicketSequence return the ticket tx consumes, false if it uses its Sequence
+func GetTicketSequence(tx data.Transaction) (uint32, bool) {
+	ticketed, ok := tx.(ticketTx)
+	if !ok {
+		return 0, false
+	}
+	return ticketed.GetTicketSequence(), true
+}
+
+// GenerateTicketCreate create count tickets for account, which take the sequences following sequence
+func GenerateTicketCreate(account data.Account, count uint32, fee data.Value, sequence uint32) (*data.TicketCreate, error) {
+	if count == 0 || count > MaxTickets {
+		return nil, fmt.Errorf("GenerateTicketCreate: ticket count %d out of range 1 to %d", count, MaxTickets)
+	}
+	tx := &data.TicketCreate{TicketCount: &count}
+	tx.TxBase = data.TxBase{
+		TransactionType: data.TICKET_CREATE,
+		Account:         account,
+		Sequence:        sequence,
+		Fee:             fee,
+	}
+	return tx, nil
+}
+
+// GenerateTicketPayment is GeneratePayment consuming ticket instead of a sequence
+func GenerateTicketPayment(from, to data.Account, amount data.Amount, fee data.Value, ticket uint32) *TicketPayment {
+	return &TicketPayment{
+		Payment:        *GeneratePayment(from, to, amount, fee, 0),
+		TicketSequence: ticket,
+	}
+}
+
+// withTicketSequence return the sdk tx of a decoded tx which consumes ticket, a TicketPayment for a
+// payment and a TicketTx for the other types
+func withTicketSequence(tx data.Transaction, ticket uint32) data.Transaction {
+	if payment, ok := tx.(*data.Payment); ok {
+		return &TicketPayment{Payment: *payment, TicketSequence: ticket}
+	}
+	return &TicketTx{Transaction: tx, TicketSequence: ticket}
+}
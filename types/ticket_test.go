@@ -0,0 +1,160 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTicketCreate(t *testing.T) {
+	owner := newTestAccount(t, "owner")
+	fee, err := data.NewNativeValue(10)
+	assert.Nil(t, err)
+	_, err = GenerateTicketCreate(owner.Account, 0, *fee, 5)
+	assert.NotNil(t, err)
+	_, err = GenerateTicketCreate(owner.Account, MaxTickets+1, *fee, 5)
+	assert.NotNil(t, err)
+	tx, err := GenerateTicketCreate(owner.Account, 3, *fee, 5)
+	assert.Nil(t, err)
+
+	txBlob, err := owner.SignTx(tx)
+	assert.Nil(t, err)
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(3), *decoded.(*data.TicketCreate).TicketCount)
+	assert.NotEqual(t, GetTicketIndex(owner.Account, 6), GetTicketIndex(owner.Account, 7))
+}
+
+func TestTicketPayment(t *testing.T) {
+	owner := newTestAccount(t, "owner")
+	signer1 := newTestAccount(t, "signer1")
+	signer2 := newTestAccount(t, "signer2")
+	amount, err := data.NewAmount("1000000")
+	assert.Nil(t, err)
+	fee, err := data.NewNativeValue(30)
+	assert.Nil(t, err)
+	payment := GenerateTicketPayment(owner.Account, signer1.Account, *amount, *fee, 7)
+	ticket, ok := GetTicketSequence(payment)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(7), ticket)
+	_, ok = GetTicketSequence(&payment.Payment)
+	assert.False(t, ok)
+
+	rawTx, err := SerializeRawMultiSignTransaction(payment)
+	assert.Nil(t, err)
+	// TicketSequence is uint32 field 41, the Sequence is 0
+	assert.True(t, strings.Contains(rawTx, "202900000007"))
+	assert.True(t, strings.Contains(rawTx, "2400000000"))
+
+	signed1, err := signer1.MultiSignTransaction(rawTx)
+	assert.Nil(t, err)
+	signed2, err := signer2.MultiSignTransaction(rawTx)
+	assert.Nil(t, err)
+	signed, ok := signed1.(*TicketPayment)
+	assert.True(t, ok)
+	assert.Equal(t, uint32(7), signed.TicketSequence)
+	assert.Equal(t, uint32(0), signed.Sequence)
+	for _, signer := range signed2.GetBase().Signers {
+		assert.Nil(t, CheckMultiSign(rawTx, signer.Signer.Account, signer.Signer.SigningPubKey.Bytes(), *signer.Signer.TxnSignature))
+	}
+	AddSigners(signed, signed2.GetBase().Signers...)
+	assert.Equal(t, 2, len(signed.Signers))
+
+	txJson, err := json.Marshal(signed)
+	assert.Nil(t, err)
+	unmarshaled, err := UnmarshalTransaction(txJson)
+	assert.Nil(t, err)
+	assert.Equal(t, signed.TicketSequence, unmarshaled.(*TicketPayment).TicketSequence)
+	_, raw, err := data.Raw(unmarshaled)
+	assert.Nil(t, err)
+	assert.Equal(t, strings.ToUpper(hex.EncodeToString(raw))[:len(rawTx)], rawTx)
+
+	payment = GenerateTicketPayment(owner.Account, signer1.Account, *amount, *fee, 8)
+	txBlob, err := owner.SignTx(payment)
+	assert.Nil(t, err)
+	decoded, err := DeserializeTransaction(txBlob)
+	assert.Nil(t, err)
+	assert.Equal(t, payment.Hash, *decoded.GetHash())
+	assert.Equal(t, uint32(8), decoded.(*TicketPayment).TicketSequence)
+	ok, err = data.CheckSignature(decoded)
+	assert.Nil(t, err)
+	assert.True(t, ok)
+}
+
+func TestTicketTx(t *testing.T) {
+	owner := newTestAccount(t, "owner")
+	destination := newTestAccount(t, "destination")
+	fee, err := data.NewNativeValue(12)
+	assert.Nil(t, err)
+	takerPays, err := data.NewAmount("1000000")
+	assert.Nil(t, err)
+	takerGets, err := data.NewAmount("10/USD/" + destination.Account.String())
+	assert.Nil(t, err)
+	offerCreate, err := GenerateOfferCreate(owner.Account, *takerPays, *takerGets, 0, 0, 0, *fee, 5)
+	assert.Nil(t, err)
+	signerListSet, err := GenerateSignerListSet(owner.Account, 1, []SignerEntry{NewSignerEntry(destination.Account, 1)}, *fee, 5)
+	assert.Nil(t, err)
+	preimage, err := GeneratePreimage()
+	assert.Nil(t, err)
+	condition, err := PreimageCondition(preimage)
+	assert.Nil(t, err)
+	escrowCreate, err := GenerateEscrowCreate(owner.Account, destination.Account, *takerPays, 100, 200, condition, *fee, 5)
+	assert.Nil(t, err)
+
+	for _, tx := range []data.Transaction{offerCreate, signerListSet, escrowCreate} {
+		ticketTx := NewTicketTx(tx, 9)
+		assert.Equal(t, uint32(0), ticketTx.GetBase().Sequence)
+		ticket, ok := GetTicketSequence(ticketTx)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(9), ticket)
+
+		txBlob, err := owner.SignTx(ticketTx)
+		assert.Nil(t, err)
+		decoded, err := DeserializeTransaction(txBlob)
+		assert.Nil(t, err)
+		decodedTicketTx, ok := decoded.(*TicketTx)
+		assert.True(t, ok)
+		assert.Equal(t, uint32(9), decodedTicketTx.TicketSequence)
+		assert.Equal(t, tx.GetTransactionType(), decoded.GetTransactionType())
+		assert.Equal(t, *ticketTx.GetHash(), *decoded.GetHash())
+		ok, err = data.CheckSignature(decoded)
+		assert.Nil(t, err)
+		assert.True(t, ok)
+		_, raw, err := data.Raw(decoded)
+		assert.Nil(t, err)
+		assert.Equal(t, txBlob, strings.ToUpper(hex.EncodeToString(raw)))
+
+		txJson, err := json.Marshal(decoded)
+		assert.Nil(t, err)
+		unmarshaled, err := UnmarshalTransaction(txJson)
+		assert.Nil(t, err)
+		assert.Equal(t, uint32(9), unmarshaled.(*TicketTx).TicketSequence)
+		_, unmarshaledRaw, err := data.Raw(unmarshaled)
+		assert.Nil(t, err)
+		assert.Equal(t, raw, unmarshaledRaw)
+	}
+	signerListSetTx := Unticketed(NewTicketTx(signerListSet, 9))
+	_, ok := signerListSetTx.(*SignerListSet)
+	assert.True(t, ok)
+}
@@ -19,8 +19,14 @@ package types
 
 import (
 	"bytes"
+	"encoding/binary"
 	"encoding/hex"
+	"encoding/json"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/rubblelabs/ripple/crypto"
 	"github.com/rubblelabs/ripple/data"
 )
 
@@ -80,7 +86,201 @@ func DeserializeRawMultiSignTx(rawTx string) (*data.Payment, error) {
 	if err != nil {
 		return nil, fmt.Errorf("deserializeRawTx: parse raw tx failed, err: %s", err)
 	}
-	payment := tx.(*data.Payment)
+	payment, ok := tx.(*data.Payment)
+	if !ok {
+		return nil, fmt.Errorf("deserializeRawTx: %s is not a payment", tx.GetTransactionType())
+	}
 	payment.InitialiseForMultiSigning()
 	return payment, nil
 }
+
+// DeserializeRawMultiSignTransaction decode the raw tx of any type to multi-sign
+func DeserializeRawMultiSignTransaction(rawTx string) (data.Transaction, error) {
+	tx, err := DeserializeTransaction(rawTx)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeRawMultiSignTransaction: %s", err)
+	}
+	tx.GetBase().InitialiseForMultiSigning()
+	return tx, nil
+}
+
+// SerializeRawMultiSignTransaction return the raw tx of tx for the signers to pass to MultiSignTransaction
+func SerializeRawMultiSignTransaction(tx data.Transaction) (string, error) {
+	tx.GetBase().InitialiseForMultiSigning()
+	_, raw, err := data.Raw(tx)
+	if err != nil {
+		return "", fmt.Errorf("SerializeRawMultiSignTransaction: serialize tx failed, err: %s", err)
+	}
+	return strings.ToUpper(hex.EncodeToString(raw)), nil
+}
+
+// UnmarshalTransaction decode the tx_json of a tx, txs whose library encoding is not canonical are
+// decoded to the sdk types
+func UnmarshalTransaction(txJson []byte) (data.Transaction, error) {
+	header := &struct {
+		TransactionType data.TransactionType
+		TicketSequence  *uint32
+	}{}
+	if err := json.Unmarshal(txJson, header); err != nil {
+		return nil, fmt.Errorf("UnmarshalTransaction: invalid TransactionType, err: %s", err)
+	}
+	tx := newTransaction(header.TransactionType)
+	if tx == nil {
+		return nil, fmt.Errorf("UnmarshalTransaction: unknown TransactionType %d", header.TransactionType)
+	}
+	if err := json.Unmarshal(txJson, tx); err != nil {
+		return nil, fmt.Errorf("UnmarshalTransaction: %s", err)
+	}
+	return canonicalTransaction(tx, &sdkFields{TicketSequence: header.TicketSequence}), nil
+}
+
+func newTransaction(txType data.TransactionType) data.Transaction {
+	switch txType {
+	case data.SIGNER_LIST_SET:
+		return &SignerListSet{TxBase: data.TxBase{TransactionType: txType}}
+	case data.ESCROW_CREATE:
+		return &EscrowCreate{TxBase: data.TxBase{TransactionType: txType}}
+	case data.ESCROW_FINISH:
+		return &EscrowFinish{TxBase: data.TxBase{TransactionType: txType}}
+	}
+	if int(txType) >= len(data.TxFactory) || data.TxFactory[txType] == nil {
+		return nil
+	}
+	return data.TxFactory[txType]()
+}
+
+// AddSigners add the signatures of signers to a multi-signed tx, signers are sorted by account as
+// rippled requires
+func AddSigners(tx data.Transaction, signers ...data.Signer) {
+	base := tx.GetBase()
+	base.InitialiseForMultiSigning()
+	base.Signers = append(base.Signers, signers...)
+	sort.Slice(base.Signers, func(i, j int) bool {
+		return base.Signers[i].Signer.Account.Less(base.Signers[j].Signer.Account)
+	})
+}
+
+// TxHash return the hash of the serialized transaction
+func TxHash(txBlob []byte) data.Hash256 {
+	var hash data.Hash256
+	copy(hash[:], crypto.Sha512Half(append(data.HP_TRANSACTION_ID.Bytes(), txBlob...)))
+	return hash
+}
+
+// DeserializeTransaction decode the tx blob returned in binary mode
+func DeserializeTransaction(txBlob string) (data.Transaction, error) {
+	txData, err := hex.DecodeString(txBlob)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTransaction: cannot decode tx blob, err: %s", err)
+	}
+	blob, fields, err := splitSdkFields(txData)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTransaction: parse tx blob failed, err: %s", err)
+	}
+	tx, err := data.ReadTransaction(bytes.NewReader(blob))
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTransaction: parse tx blob failed, err: %s", err)
+	}
+	tx = canonicalTransaction(tx, fields)
+	*tx.GetHash() = TxHash(txData)
+	return tx, nil
+}
+
+// DeserializeTxWithMeta decode the tx blob and metadata blob returned in binary mode
+func DeserializeTxWithMeta(txBlob, meta string, ledger uint32) (*data.TransactionWithMetaData, error) {
+	txData, err := hex.DecodeString(txBlob)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTxWithMeta: cannot decode tx blob, err: %s", err)
+	}
+	metaData, err := hex.DecodeString(meta)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTxWithMeta: cannot decode meta, err: %s", err)
+	}
+	blob, fields, err := splitSdkFields(txData)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTxWithMeta: parse tx failed, err: %s", err)
+	}
+	txm, err := data.ReadTransactionAndMetadata(bytes.NewReader(blob), bytes.NewReader(metaData), TxHash(txData), ledger)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeTxWithMeta: parse tx failed, err: %s", err)
+	}
+	txm.Transaction = canonicalTransaction(txm.Transaction, fields)
+	return txm, nil
+}
+
+const (
+	sfTicketSequence = 41 // uint32
+	sfFulfillment    = 16 // vl
+	sfCondition      = 17 // vl
+)
+
+// sdkFields are the fields of the sdk txs which the library txs lack, they are split off a tx blob
+// before the library decodes it and set on the sdk tx
+type sdkFields struct {
+	TicketSequence *uint32
+	Condition      *data.VariableLength
+	Fulfillment    *data.VariableLength
+}
+
+func splitSdkFields(txData []byte) ([]byte, *sdkFields, error) {
+	parsed, err := parseSTObject(txData)
+	if err != nil {
+		return nil, nil, err
+	}
+	fields := &sdkFields{}
+	rest := make([]byte, 0, len(txData))
+	for _, field := range parsed {
+		switch {
+		case field.is(stUint32, sfTicketSequence):
+			ticket := binary.BigEndian.Uint32(field.value)
+			fields.TicketSequence = &ticket
+		case field.is(stVL, sfCondition):
+			condition := data.VariableLength(field.value)
+			fields.Condition = &condition
+		case field.is(stVL, sfFulfillment):
+			fulfillment := data.VariableLength(field.value)
+			fields.Fulfillment = &fulfillment
+		default:
+			rest = append(rest, field.raw...)
+		}
+	}
+	return rest, fields, nil
+}
+
+// canonicalTransaction replace the library txs whose encoding is not canonical or which lack fields
+// with the sdk ones, so that the signature and hash of a decoded tx can be checked. fields are the
+// fields split off the blob, a tx with a TicketSequence is wrapped after being made canonical
+func canonicalTransaction(tx data.Transaction, fields *sdkFields) data.Transaction {
+	switch v := tx.(type) {
+	case *data.SignerListSet:
+		signerListSet := &SignerListSet{TxBase: v.TxBase, SignerQuorum: v.SignerQuorum}
+		for _, entry := range v.SignerEntries {
+			if entry.Account != nil && entry.SignerWeight != nil {
+				signerListSet.SignerEntries = append(signerListSet.SignerEntries, NewSignerEntry(*entry.Account, *entry.SignerWeight))
+			}
+		}
+		tx = signerListSet
+	case *data.EscrowCreate:
+		tx = &EscrowCreate{
+			TxBase:         v.TxBase,
+			Destination:    v.Destination,
+			Amount:         v.Amount,
+			Condition:      fields.Condition,
+			CancelAfter:    v.CancelAfter,
+			FinishAfter:    v.FinishAfter,
+			DestinationTag: v.DestinationTag,
+		}
+	case *data.EscrowFinish:
+		tx = &EscrowFinish{
+			TxBase:        v.TxBase,
+			Owner:         v.Owner,
+			OfferSequence: v.OfferSequence,
+			Condition:     fields.Condition,
+			Fulfillment:   fields.Fulfillment,
+		}
+	}
+	if fields.TicketSequence != nil {
+		return withTicketSequence(tx, *fields.TicketSequence)
+	}
+	return tx
+}
@@ -0,0 +1,48 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/hex"
+	"testing"
+
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+const (
+	testTxBlob = "1200002280000000240003807961d4c6c00a3912c00000000000000000000000000044564300000000005436e447c4dd1fa6ad2950a75df6d6d9ce1e80f068400000000000000a732103304b7f7f7c1d54d6fbeb8094052719017619edec4ecec6a2023f01b1609ad1697446304402203a75b1e415800dc9ae04a33b0a1edf5f23d64129c8f6b06d807920b4f933b2e1022003009ea571409ae6facd428f7333c4d8e04f50d711cdbf2e4dd4ec9e9823d17e81145436e447c4dd1fa6ad2950a75df6d6d9ce1e80f083143603d5ffde90d7a862fc3b914702de7515380a3d"
+	testTxMeta = "201c00000005f8e51100612500698e825548165c04abec9ede8683d2defaa6e04ff426534e29fc05b25dfd28887582097f5634d7f0641a0467bc06c748101789695a0f4a16bd68fc05984ed4e2185decc8d7e624000380796240000000492d4807e1e72200000000240003807a2d000000006240000000492d47fd81145436e447c4dd1fa6ad2950a75df6d6d9ce1e80f0e1e1f1031080"
+)
+
+func TestDeserializeTxWithMeta(t *testing.T) {
+	tx, err := DeserializeTransaction(testTxBlob)
+	assert.Nil(t, err)
+	assert.Equal(t, data.PAYMENT, tx.GetTransactionType())
+
+	txm, err := DeserializeTxWithMeta(testTxBlob, testTxMeta, 6917762)
+	assert.Nil(t, err)
+	assert.Equal(t, *tx.GetHash(), *txm.GetHash())
+	assert.Equal(t, uint32(6917762), txm.LedgerSequence)
+	assert.Equal(t, uint32(5), txm.MetaData.TransactionIndex)
+	assert.Equal(t, "tecPATH_DRY", txm.MetaData.TransactionResult.String())
+
+	_, raw, err := data.Raw(tx)
+	assert.Nil(t, err)
+	assert.Equal(t, testTxBlob, hex.EncodeToString(raw))
+}
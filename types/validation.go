@@ -0,0 +1,208 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math"
+
+	"github.com/rubblelabs/ripple/data"
+)
+
+// vfFullValidation is set on validations of fully validated ledgers, partial validations don't count
+const vfFullValidation uint32 = 0x00000001
+
+// Validation is a STValidation signed by the ephemeral key of a validator
+type Validation struct {
+	Flags          uint32
+	LedgerSequence uint32
+	SigningTime    uint32
+	LedgerHash     data.Hash256
+	SigningKey     []byte
+	Signature      []byte
+	signingData    []byte
+}
+
+// ParseValidation decode the validation in hex, as the data field of the validations stream
+func ParseValidation(validation string) (*Validation, error) {
+	raw, err := hex.DecodeString(validation)
+	if err != nil {
+		return nil, fmt.Errorf("ParseValidation: cannot decode hex, err: %s", err)
+	}
+	return DeserializeValidation(raw)
+}
+
+// DeserializeValidation decode the serialized validation
+func DeserializeValidation(raw []byte) (*Validation, error) {
+	fields, err := parseSTObject(raw)
+	if err != nil {
+		return nil, fmt.Errorf("DeserializeValidation: %s", err)
+	}
+	v := &Validation{signingData: signingData(fields, sfSignature)}
+	hasLedgerHash := false
+	for _, field := range fields {
+		switch {
+		case field.is(stUint32, sfFlags):
+			v.Flags = binary.BigEndian.Uint32(field.value)
+		case field.is(stUint32, sfLedgerSequence):
+			v.LedgerSequence = binary.BigEndian.Uint32(field.value)
+		case field.is(stUint32, sfSigningTime):
+			v.SigningTime = binary.BigEndian.Uint32(field.value)
+		case field.is(stHash256, sfLedgerHash):
+			copy(v.LedgerHash[:], field.value)
+			hasLedgerHash = true
+		case field.is(stVL, sfSigningPubKey):
+			v.SigningKey = field.value
+		case field.is(stVL, sfSignature):
+			v.Signature = field.value
+		}
+	}
+	if !hasLedgerHash || len(v.SigningKey) != 33 || len(v.Signature) == 0 {
+		return nil, fmt.Errorf("DeserializeValidation: missing ledger hash, signing key or signature")
+	}
+	return v, nil
+}
+
+// Full return whether the validation is a full validation
+func (this *Validation) Full() bool {
+	return this.Flags&vfFullValidation != 0
+}
+
+// Verify check the signature of the validation by its signing key
+func (this *Validation) Verify() error {
+	msg := append(data.HP_VALIDATION.Bytes(), this.signingData...)
+	if err := verifySignature(this.SigningKey, sha512Half(msg), msg, this.Signature); err != nil {
+		return fmt.Errorf("Validation.Verify: signature of %s invalid, err: %s", NodePublicKeyString(this.SigningKey), err)
+	}
+	return nil
+}
+
+// UNL is the list of trusted validators identified by their master keys
+type UNL struct {
+	quorum     int
+	masterKeys map[string]bool
+	manifests  map[string]*Manifest
+	signingKey map[string]string
+}
+
+// NewUNL create the UNL of master keys in the n9 base58 format
+func NewUNL(masterKeys []string) (*UNL, error) {
+	unl := &UNL{
+		masterKeys: make(map[string]bool),
+		manifests:  make(map[string]*Manifest),
+		signingKey: make(map[string]string),
+	}
+	for _, key := range masterKeys {
+		raw, err := ParseNodePublicKey(key)
+		if err != nil {
+			return nil, fmt.Errorf("NewUNL: %s", err)
+		}
+		unl.masterKeys[string(raw)] = true
+	}
+	if len(unl.masterKeys) == 0 {
+		return nil, fmt.Errorf("NewUNL: empty validator list")
+	}
+	unl.quorum = int(math.Ceil(float64(len(unl.masterKeys)) * 0.8))
+	return unl, nil
+}
+
+// Quorum return the number of trusted validations required, the default is 80% of the validators
+func (this *UNL) Quorum() int {
+	return this.quorum
+}
+
+// SetQuorum set the number of trusted validations required, it must be at least 1
+func (this *UNL) SetQuorum(quorum int) error {
+	if quorum < 1 {
+		return fmt.Errorf("SetQuorum: quorum %d must be at least 1", quorum)
+	}
+	this.quorum = quorum
+	return nil
+}
+
+// Len return the number of trusted validators
+func (this *UNL) Len() int {
+	return len(this.masterKeys)
+}
+
+// ApplyManifest verify the manifest and record the signing key of the validator if it is trusted
+// and the manifest is newer than the known one
+func (this *UNL) ApplyManifest(m *Manifest) error {
+	master := string(m.MasterKey)
+	if !this.masterKeys[master] {
+		return fmt.Errorf("ApplyManifest: validator %s is not trusted", m.MasterKeyString())
+	}
+	if err := m.Verify(); err != nil {
+		return fmt.Errorf("ApplyManifest: %s", err)
+	}
+	if old, ok := this.manifests[master]; ok {
+		if m.Sequence <= old.Sequence {
+			return fmt.Errorf("ApplyManifest: stale manifest of %s, sequence %d, known %d",
+				m.MasterKeyString(), m.Sequence, old.Sequence)
+		}
+		delete(this.signingKey, string(old.SigningKey))
+	}
+	this.manifests[master] = m
+	if !m.Revoked() {
+		this.signingKey[string(m.SigningKey)] = master
+	}
+	return nil
+}
+
+// masterKey return the trusted master key of a signing key, a validator without manifest signs with its master key
+func (this *UNL) masterKey(signingKey []byte) (string, bool) {
+	if master, ok := this.signingKey[string(signingKey)]; ok {
+		return master, true
+	}
+	key := string(signingKey)
+	if !this.masterKeys[key] {
+		return "", false
+	}
+	if m, ok := this.manifests[key]; ok && (m.Revoked() || string(m.SigningKey) != key) {
+		return "", false
+	}
+	return key, true
+}
+
+// CheckQuorum check that enough distinct trusted validators signed a full validation of the ledger
+func (this *UNL) CheckQuorum(ledgerHash data.Hash256, validations []*Validation) error {
+	if this.quorum < 1 {
+		return fmt.Errorf("CheckQuorum: quorum %d is invalid, the UNL must be created by NewUNL", this.quorum)
+	}
+	signed := make(map[string]bool)
+	for _, v := range validations {
+		if v.LedgerHash != ledgerHash || !v.Full() {
+			continue
+		}
+		master, ok := this.masterKey(v.SigningKey)
+		if !ok || signed[master] {
+			continue
+		}
+		if err := v.Verify(); err != nil {
+			continue
+		}
+		signed[master] = true
+	}
+	if len(signed) < this.quorum {
+		return fmt.Errorf("CheckQuorum: ledger %s has %d trusted validations, quorum is %d",
+			ledgerHash.String(), len(signed), this.quorum)
+	}
+	return nil
+}
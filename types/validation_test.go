@@ -0,0 +1,166 @@
+/*
+* Copyright (C) 2020 The poly network Authors
+* This file is part of The poly network library.
+*
+* The poly network is free software: you can redistribute it and/or modify
+* it under the terms of the GNU Lesser General Public License as published by
+* the Free Software Foundation, either version 3 of the License, or
+* (at your option) any later version.
+*
+* The poly network is distributed in the hope that it will be useful,
+* but WITHOUT ANY WARRANTY; without even the implied warranty of
+* MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE. See the
+* GNU Lesser General Public License for more details.
+* You should have received a copy of the GNU Lesser General Public License
+* along with The poly network . If not, see <http://www.gnu.org/licenses/>.
+ */
+
+package types
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"testing"
+
+	"github.com/rubblelabs/ripple/crypto"
+	"github.com/rubblelabs/ripple/data"
+	"github.com/stretchr/testify/assert"
+)
+
+func vlField(header []byte, value []byte) []byte {
+	return append(append(header, byte(len(value))), value...)
+}
+
+func uint32Field(header byte, value uint32) []byte {
+	buf := []byte{header, 0, 0, 0, 0}
+	binary.BigEndian.PutUint32(buf[1:], value)
+	return buf
+}
+
+func sign(t *testing.T, key crypto.Key, prefix data.HashPrefix, signingData []byte) []byte {
+	msg := append(prefix.Bytes(), signingData...)
+	hash := sha512Half(msg)
+	sig, err := crypto.Sign(key.Private(nil), hash[:], msg)
+	assert.Nil(t, err)
+	return sig
+}
+
+func newManifest(t *testing.T, master, ephemeral crypto.Key, sequence uint32) string {
+	signingData := uint32Field(0x24, sequence)
+	signingData = append(signingData, vlField([]byte{0x71}, master.Public(nil))...)
+	signingData = append(signingData, vlField([]byte{0x73}, ephemeral.Public(nil))...)
+	raw := append([]byte{}, signingData...)
+	raw = append(raw, vlField([]byte{0x76}, sign(t, ephemeral, HP_MANIFEST, signingData))...)
+	raw = append(raw, vlField([]byte{0x70, 0x12}, sign(t, master, HP_MANIFEST, signingData))...)
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func newValidation(t *testing.T, key crypto.Key, ledgerHash data.Hash256, flags uint32) *Validation {
+	signingData := uint32Field(0x22, flags)
+	signingData = append(signingData, uint32Field(0x26, 32570)...)
+	signingData = append(signingData, uint32Field(0x29, 410325671)...)
+	signingData = append(signingData, append([]byte{0x51}, ledgerHash[:]...)...)
+	signingData = append(signingData, vlField([]byte{0x73}, key.Public(nil))...)
+	raw := append(signingData, vlField([]byte{0x76}, sign(t, key, data.HP_VALIDATION, signingData))...)
+	v, err := DeserializeValidation(raw)
+	assert.Nil(t, err)
+	return v
+}
+
+func newValidatorKeys(t *testing.T, i byte) (crypto.Key, crypto.Key) {
+	master, err := crypto.NewEd25519Key([]byte{'m', i})
+	assert.Nil(t, err)
+	ephemeral, err := crypto.NewEd25519Key([]byte{'e', i})
+	assert.Nil(t, err)
+	return master, ephemeral
+}
+
+func TestManifest(t *testing.T) {
+	master, _ := newValidatorKeys(t, 0)
+	ephemeral, err := crypto.NewECDSAKey([]byte("ephemeral"))
+	assert.Nil(t, err)
+	assert.Equal(t, 32, len(ephemeral.Private(nil)))
+
+	m, err := ParseManifest(newManifest(t, master, ephemeral, 1))
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(1), m.Sequence)
+	assert.Equal(t, master.Public(nil), m.MasterKey)
+	assert.Nil(t, m.Verify())
+
+	m.Sequence = 2
+	m.signingData[4] = 2
+	assert.NotNil(t, m.Verify())
+}
+
+func TestCheckQuorum(t *testing.T) {
+	var masterKeys []string
+	var ephemerals []crypto.Key
+	var manifests []*Manifest
+	for i := byte(0); i < 5; i++ {
+		master, ephemeral := newValidatorKeys(t, i)
+		m, err := ParseManifest(newManifest(t, master, ephemeral, 1))
+		assert.Nil(t, err)
+		masterKeys = append(masterKeys, m.MasterKeyString())
+		ephemerals = append(ephemerals, ephemeral)
+		manifests = append(manifests, m)
+	}
+	unl, err := NewUNL(masterKeys)
+	assert.Nil(t, err)
+	assert.Equal(t, 4, unl.Quorum())
+	assert.NotNil(t, unl.SetQuorum(0))
+	assert.Equal(t, 4, unl.Quorum())
+	for _, m := range manifests {
+		assert.Nil(t, unl.ApplyManifest(m))
+	}
+	assert.NotNil(t, unl.ApplyManifest(manifests[0]))
+
+	ledgerHash := sha512Half([]byte("ledger"))
+	var validations []*Validation
+	for i := 0; i < 3; i++ {
+		v := newValidation(t, ephemerals[i], ledgerHash, vfFullValidation)
+		assert.Nil(t, v.Verify())
+		validations = append(validations, v, v)
+	}
+	assert.NotNil(t, unl.CheckQuorum(ledgerHash, validations))
+
+	partial := newValidation(t, ephemerals[3], ledgerHash, 0)
+	assert.NotNil(t, unl.CheckQuorum(ledgerHash, append(validations, partial)))
+
+	untrusted, _ := newValidatorKeys(t, 9)
+	other := newValidation(t, untrusted, ledgerHash, vfFullValidation)
+	assert.NotNil(t, unl.CheckQuorum(ledgerHash, append(validations, other)))
+
+	full := newValidation(t, ephemerals[3], ledgerHash, vfFullValidation)
+	assert.Nil(t, unl.CheckQuorum(ledgerHash, append(validations, full)))
+	assert.NotNil(t, unl.CheckQuorum(sha512Half([]byte("fork")), append(validations, full)))
+
+	assert.Nil(t, unl.SetQuorum(5))
+	assert.NotNil(t, unl.CheckQuorum(ledgerHash, append(validations, full)))
+	assert.NotNil(t, new(UNL).CheckQuorum(ledgerHash, nil))
+}
+
+// mainnet validations of ledgers 6951500 and 6951734, as in the fixtures of rubblelabs/ripple
+var mainnetValidations = []string{
+	"228000000026006A124C291B1DBFA6511A8194A501C8C9AC779A96495365D596371C09636E63F62BB0B4B81CF1239BAF732103280B1651DD14F4A56D834ACBE6637645032D871D0BDFF3EC0B8335A021EEC6C276473045022100FEFADD500D6B9E0086885943EE299378FD7A46E2780211468141B798B8756816022006F462B93BDA3D105F559B3B1824854054BD7BE346D9EC70EFEF13558E834992",
+	"228000000026006A1336291B1DC46751B1EF9D91B9102381B93C8E38FCDA8ED59543AF44AC72BAF0A613EAE76F586E2F732102ACAA0A6AB8C6BAD6495DF58C1A5ADB9BC3054304743DEEA5F68B6B5560CCD15E76463044022071F94FAEEB5E72DA252C14C2AF28F5C8EB7C411F65C9BBA472943ACF66E23DD40220204E81EE4826776FC438D0B074A8A7AD4823AFDEB24A3B6B15BEB64D304D9E52",
+}
+
+func TestMainnetValidation(t *testing.T) {
+	v, err := ParseValidation(mainnetValidations[0])
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(0x80000000), v.Flags)
+	assert.Equal(t, uint32(6951500), v.LedgerSequence)
+	assert.Equal(t, uint32(454934438), v.SigningTime)
+	assert.Equal(t, "1A8194A501C8C9AC779A96495365D596371C09636E63F62BB0B4B81CF1239BAF", v.LedgerHash.String())
+	assert.False(t, v.Full())
+	assert.Nil(t, v.Verify())
+
+	v, err = ParseValidation(mainnetValidations[1])
+	assert.Nil(t, err)
+	assert.Equal(t, uint32(6951734), v.LedgerSequence)
+	assert.Nil(t, v.Verify())
+
+	v.Signature = append([]byte{}, v.Signature...)
+	v.Signature[10] ^= 1
+	assert.NotNil(t, v.Verify())
+}